@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pressly/cli/xflag"
+)
+
+// ExpandAliases expands a user-defined alias in the first argument, in the style of git's
+// "alias.st = status --short" configuration. aliases maps an alias name to the argument string it
+// stands for, which is split with [xflag.SplitCommandString]. Aliases may refer to other aliases;
+// a cycle is reported as an error. Arguments that are not an alias are returned unchanged.
+//
+// Aliases are typically loaded from the application's configuration file and applied from the
+// root command's PreParse hook:
+//
+//	root.PreParse = func(args []string) ([]string, error) {
+//	    return cli.ExpandAliases(args, cfg.Aliases)
+//	}
+//
+// Only the first argument is expanded, so an alias cannot take the place of a flag value or
+// positional argument.
+func ExpandAliases(args []string, aliases map[string]string) ([]string, error) {
+	var seen []string
+	for len(args) > 0 {
+		name := args[0]
+		expansion, ok := aliases[name]
+		if !ok {
+			break
+		}
+		if slices.Contains(seen, name) {
+			return nil, fmt.Errorf("alias loop: %s -> %s", strings.Join(seen, " -> "), name)
+		}
+		seen = append(seen, name)
+		expanded, err := xflag.SplitCommandString(expansion)
+		if err != nil {
+			return nil, fmt.Errorf("alias %q: %w", name, err)
+		}
+		if len(expanded) == 0 {
+			return nil, fmt.Errorf("alias %q: empty expansion", name)
+		}
+		args = append(expanded, args[1:]...)
+	}
+	return args, nil
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandAliases(t *testing.T) {
+	t.Parallel()
+
+	aliases := map[string]string{
+		"st":    "status --short",
+		"sts":   "st --branch",
+		"loop":  "again",
+		"again": "loop",
+		"bad":   `log --format="%h`,
+		"none":  "",
+	}
+
+	t.Run("expands first argument", func(t *testing.T) {
+		t.Parallel()
+		args, err := ExpandAliases([]string{"st", "docs/"}, aliases)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"status", "--short", "docs/"}, args)
+	})
+	t.Run("nested aliases", func(t *testing.T) {
+		t.Parallel()
+		args, err := ExpandAliases([]string{"sts"}, aliases)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"status", "--short", "--branch"}, args)
+	})
+	t.Run("no alias", func(t *testing.T) {
+		t.Parallel()
+		args, err := ExpandAliases([]string{"status", "st"}, aliases)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"status", "st"}, args)
+
+		args, err = ExpandAliases(nil, aliases)
+		require.NoError(t, err)
+		assert.Empty(t, args)
+	})
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		_, err := ExpandAliases([]string{"loop"}, aliases)
+		assert.EqualError(t, err, "alias loop: loop -> again -> loop")
+		_, err = ExpandAliases([]string{"bad"}, aliases)
+		assert.ErrorContains(t, err, `alias "bad": `)
+		_, err = ExpandAliases([]string{"none"}, aliases)
+		assert.EqualError(t, err, `alias "none": empty expansion`)
+	})
+}
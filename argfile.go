@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pressly/cli/xflag"
+)
+
+// expandArgFiles replaces every "@path" argument before the first "--" with the arguments read
+// from the file at path. Arguments in the file are separated by whitespace or newlines and follow
+// the quoting rules of [xflag.SplitCommandString].
+//
+// A relative path is read from dir, or the working directory if dir is empty. If no regular file
+// exists at path, the argument is kept as-is so that values like "@username" or "@dir" keep
+// working. Arguments read from a file are not expanded again.
+//
+// Commands reached through root with AllowUnknownFlags or FlagsStopAtFirstArg pass their
+// arguments on to another program, so expansion stops at their first positional argument: in
+// "app exec curl -d @body.json", the "@body.json" is for curl.
+func expandArgFiles(root *Command, args []string, dir string) ([]string, error) {
+	w := argWalker{root: root, current: root, path: []*Command{root}}
+	var out []string
+	for i, arg := range args {
+		if arg == "--" || w.passthrough {
+			out = append(out, args[i:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '@' || w.atPassthroughArg() {
+			out = append(out, arg)
+			w.visit(arg)
+			continue
+		}
+		path := arg[1:]
+		name := path
+		if dir != "" && !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+		info, err := os.Stat(name)
+		if errors.Is(err, fs.ErrNotExist) || (err == nil && !info.Mode().IsRegular()) {
+			out = append(out, arg)
+			w.visit(arg)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("argument file %q: %w", path, err)
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("argument file %q: %w", path, err)
+		}
+		expanded, err := xflag.SplitCommandString(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("argument file %q: %w", path, err)
+		}
+		out = append(out, expanded...)
+		for _, arg := range expanded {
+			w.visit(arg)
+		}
+	}
+	return out, nil
+}
+
+// argWalker follows the command path through the arguments the way resolveCommandPath does, to
+// find where a passthrough command's positional arguments begin before the path is resolved.
+type argWalker struct {
+	root, current *Command
+	path          []*Command
+	// skipValue is set when the previous argument was a flag that takes the next one as its value.
+	skipValue bool
+	// passthrough is set once a positional argument of a passthrough command has been seen.
+	passthrough bool
+}
+
+// atPassthroughArg reports whether a non-flag argument in the current position would be the first
+// positional argument of a passthrough command.
+func (w *argWalker) atPassthroughArg() bool {
+	return !w.skipValue && (w.current.AllowUnknownFlags || w.current.FlagsStopAtFirstArg)
+}
+
+// visit advances the walker past arg.
+func (w *argWalker) visit(arg string) {
+	if w.skipValue {
+		w.skipValue = false
+		return
+	}
+	if strings.HasPrefix(arg, "-") && arg != "-" {
+		name, hasValue := splitFlagArg(arg)
+		w.skipValue = !hasValue && flagTakesValue(w.path, name, w.root.MatchCase.foldFlags())
+		return
+	}
+	fold := w.root.MatchCase.foldCommands()
+	sub := w.current.findSubCommand(arg, fold)
+	if sub == nil && w.root.PrefixMatching {
+		sub, _ = w.current.findSubCommandByPrefix(arg, fold)
+	}
+	if sub != nil {
+		w.current = sub
+		w.path = append(w.path, sub)
+		return
+	}
+	if w.current.AllowUnknownFlags || w.current.FlagsStopAtFirstArg {
+		w.passthrough = true
+	}
+}
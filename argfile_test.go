@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgFiles(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func() *Command {
+		return &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.String("name", "", "the name")
+				f.Bool("force", false, "force")
+			}),
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+	}
+	writeFile := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "args.txt")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	t.Run("expands file contents", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "--name \"hello world\"\n--force\n  arg1 'arg 2'\n")
+		root := newRoot()
+		err := Parse(root, []string{"@" + path, "arg3"})
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", GetFlag[string](root.state, "name"))
+		assert.True(t, GetFlag[bool](root.state, "force"))
+		assert.Equal(t, []string{"arg1", "arg 2", "arg3"}, root.state.Args)
+	})
+	t.Run("missing file kept literally", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		err := Parse(root, []string{"@username", "@"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"@username", "@"}, root.state.Args)
+	})
+	t.Run("not expanded after delimiter", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "--force")
+		root := newRoot()
+		err := Parse(root, []string{"--", "@" + path})
+		require.NoError(t, err)
+		assert.False(t, GetFlag[bool](root.state, "force"))
+		assert.Equal(t, []string{"@" + path}, root.state.Args)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "--force")
+		root := newRoot()
+		root.DisableArgFiles = true
+		err := Parse(root, []string{"@" + path})
+		require.NoError(t, err)
+		assert.False(t, GetFlag[bool](root.state, "force"))
+		assert.Equal(t, []string{"@" + path}, root.state.Args)
+	})
+	t.Run("directory kept literally", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		root := newRoot()
+		err := Parse(root, []string{"@" + dir})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"@" + dir}, root.state.Args)
+	})
+	t.Run("passthrough commands", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "--force")
+		exec := func(ctx context.Context, s *State) error { return nil }
+		for _, sub := range []*Command{
+			{Name: "exec", AllowUnknownFlags: true, Exec: exec},
+			{Name: "exec", FlagsStopAtFirstArg: true, Exec: exec},
+		} {
+			root := newRoot()
+			root.SubCommands = []*Command{sub}
+			err := Parse(root, []string{"@" + path, "exec", "--name", "@" + path, "curl", "-d", "@" + path})
+			require.NoError(t, err)
+			assert.True(t, GetFlag[bool](root.state, "force"))
+			assert.Equal(t, "--force", GetFlag[string](root.state, "name"))
+			assert.Equal(t, []string{"curl", "-d", "@" + path}, root.state.Args)
+
+			root = newRoot()
+			root.SubCommands = []*Command{sub}
+			err = Parse(root, []string{"exec", "@" + path})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"@" + path}, root.state.Args)
+		}
+	})
+	t.Run("unterminated quote", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "--name 'oops")
+		err := Parse(newRoot(), []string{"@" + path})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unterminated ' quote")
+	})
+}
@@ -0,0 +1,69 @@
+// Package clitest provides helpers for testing commands built with the cli package. It takes care
+// of the buffer, [cli.Parse], and [cli.Run] plumbing so tests can focus on arguments and output:
+//
+//	func TestAdd(t *testing.T) {
+//	    res := clitest.Run(t, newRoot(), "task", "add", "--tags=x", "buy milk")
+//	    require.NoError(t, res.Err)
+//	    require.Equal(t, "added: buy milk\n", res.Stdout)
+//	}
+//
+// Commands are parsed and run with [cli.ParseAndRun], so help requests print usage to Stdout and
+// return a nil error, exactly as they do for users.
+package clitest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pressly/cli"
+)
+
+// Result holds the outcome of running a command.
+type Result struct {
+	// Stdout and Stderr hold everything the command wrote to its output streams, including log
+	// output from [cli.State].Logger, which writes to Stderr by default.
+	Stdout, Stderr string
+
+	// Err is the error returned by parsing or running the command, nil on success.
+	Err error
+}
+
+// Run parses and runs root with args and returns the captured output and error. The first argument
+// is the root command's name, mirroring os.Args, and is skipped if it matches root.Name; this lets
+// tests read like the command line they exercise. Stdin is empty.
+//
+// Run parses root in place. To run the same command definition from parallel tests, pass a fresh
+// tree or a [cli.Command.Clone] to each call.
+func Run(t testing.TB, root *cli.Command, args ...string) *Result {
+	t.Helper()
+	return RunWithStdin(t, root, nil, args...)
+}
+
+// RunWithStdin is like [Run] but provides stdin as the command's standard input. A nil stdin is
+// treated as empty input.
+func RunWithStdin(t testing.TB, root *cli.Command, stdin io.Reader, args ...string) *Result {
+	t.Helper()
+	if root == nil {
+		t.Fatal("clitest: root command is nil")
+	}
+	if stdin == nil {
+		stdin = strings.NewReader("")
+	}
+	if len(args) > 0 && args[0] == root.Name {
+		args = args[1:]
+	}
+	var stdout, stderr bytes.Buffer
+	err := cli.ParseAndRun(context.Background(), root, args, &cli.RunOptions{
+		Stdin:  stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	return &Result{
+		Stdout: stdout.String(),
+		Stderr: stderr.String(),
+		Err:    err,
+	}
+}
@@ -0,0 +1,101 @@
+package clitest
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pressly/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRoot() *cli.Command {
+	return &cli.Command{
+		Name:      "task",
+		ShortHelp: "manage tasks",
+		SubCommands: []*cli.Command{
+			{
+				Name:      "add",
+				ShortHelp: "add a task",
+				Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+					f.String("tags", "", "comma-separated tags")
+				}),
+				Exec: func(ctx context.Context, s *cli.State) error {
+					if len(s.Args) == 0 {
+						return errors.New("missing task")
+					}
+					fmt.Fprintf(s.Stdout, "added: %s [%s]\n", strings.Join(s.Args, " "), cli.GetFlag[string](s, "tags"))
+					fmt.Fprintln(s.Stderr, "1 task added")
+					return nil
+				},
+			},
+			{
+				Name:      "import",
+				ShortHelp: "import tasks from stdin",
+				Exec: func(ctx context.Context, s *cli.State) error {
+					data, err := io.ReadAll(s.Stdin)
+					if err != nil {
+						return err
+					}
+					fmt.Fprintf(s.Stdout, "imported %d lines\n", strings.Count(string(data), "\n"))
+					return nil
+				},
+			},
+		},
+		Exec: func(ctx context.Context, s *cli.State) error { return nil },
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	t.Run("captures output", func(t *testing.T) {
+		t.Parallel()
+		res := Run(t, newRoot(), "task", "add", "--tags=x", "buy milk")
+		require.NoError(t, res.Err)
+		assert.Equal(t, "added: buy milk [x]\n", res.Stdout)
+		assert.Equal(t, "1 task added\n", res.Stderr)
+	})
+	t.Run("without program name", func(t *testing.T) {
+		t.Parallel()
+		res := Run(t, newRoot(), "add", "buy milk")
+		require.NoError(t, res.Err)
+		assert.Equal(t, "added: buy milk []\n", res.Stdout)
+	})
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+		res := Run(t, newRoot(), "task", "add")
+		require.EqualError(t, res.Err, "missing task")
+		assert.Empty(t, res.Stdout)
+	})
+	t.Run("parse error", func(t *testing.T) {
+		t.Parallel()
+		res := Run(t, newRoot(), "task", "add", "--unknown")
+		require.Error(t, res.Err)
+		assert.Contains(t, res.Err.Error(), "flag provided but not defined")
+	})
+	t.Run("help", func(t *testing.T) {
+		t.Parallel()
+		res := Run(t, newRoot(), "task", "--help")
+		require.NoError(t, res.Err)
+		assert.Contains(t, res.Stdout, "manage tasks")
+		assert.Contains(t, res.Stdout, "import")
+	})
+	t.Run("empty stdin", func(t *testing.T) {
+		t.Parallel()
+		res := Run(t, newRoot(), "task", "import")
+		require.NoError(t, res.Err)
+		assert.Equal(t, "imported 0 lines\n", res.Stdout)
+	})
+	t.Run("with stdin", func(t *testing.T) {
+		t.Parallel()
+		res := RunWithStdin(t, newRoot(), strings.NewReader("a\nb\n"), "task", "import")
+		require.NoError(t, res.Err)
+		assert.Equal(t, "imported 2 lines\n", res.Stdout)
+	})
+}
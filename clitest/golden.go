@@ -0,0 +1,104 @@
+package clitest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pressly/cli"
+)
+
+// updateEnv is the environment variable that makes [GoldenHelp] write golden files instead of
+// comparing against them.
+const updateEnv = "CLITEST_UPDATE"
+
+// Help returns the help text [cli.ParseAndRun] prints for the command at path, such as
+// Help(t, root, "list", "archived"). An empty path renders the root command's help. The root is
+// cloned first, so the tree passed in is not modified.
+func Help(t testing.TB, root *cli.Command, path ...string) string {
+	t.Helper()
+	if root == nil {
+		t.Fatal("clitest: root command is nil")
+	}
+	clone := root.Clone()
+	args := append(append([]string(nil), path...), "--help")
+	if err := cli.Parse(clone, args); !errors.Is(err, cli.ErrHelp) {
+		t.Fatalf("clitest: rendering help for %q: %v", strings.Join(path, " "), err)
+	}
+	return cli.DefaultUsage(clone) + "\n"
+}
+
+// GoldenHelp renders the help text of every command in the tree and compares it against golden
+// files in dir, one per command, named after the command path joined by underscores (for example
+// "todo_list_archived.golden"). Each mismatch is reported as a test error.
+//
+// Run the tests with CLITEST_UPDATE set to a true value, like 1, to write the current output to the
+// golden files instead:
+//
+//	CLITEST_UPDATE=1 go test ./... -run TestHelp
+//
+// An environment variable is used rather than a test flag, so test packages are free to define
+// their own -update flag.
+func GoldenHelp(t testing.TB, root *cli.Command, dir string) {
+	t.Helper()
+	if root == nil {
+		t.Fatal("clitest: root command is nil")
+	}
+	walk(root, nil, func(names []string) {
+		t.Helper()
+		got := Help(t, root, names[1:]...)
+		file := filepath.Join(dir, strings.Join(names, "_")+".golden")
+		if update, _ := strconv.ParseBool(os.Getenv(updateEnv)); update {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatalf("clitest: %v", err)
+			}
+			if err := os.WriteFile(file, []byte(got), 0o644); err != nil {
+				t.Fatalf("clitest: %v", err)
+			}
+			return
+		}
+		want, err := os.ReadFile(file)
+		if err != nil {
+			t.Errorf("clitest: %v (run with CLITEST_UPDATE=1 to create it)", err)
+			return
+		}
+		if got != string(want) {
+			t.Errorf("clitest: help for %q does not match %s (run with CLITEST_UPDATE=1 to refresh)\n%s",
+				strings.Join(names, " "), file, diff(string(want), got))
+		}
+	})
+}
+
+// walk calls fn with the command path of cmd and of every command below it, parents first.
+func walk(cmd *cli.Command, parents []string, fn func(names []string)) {
+	names := append(append([]string(nil), parents...), cmd.Name)
+	fn(names)
+	for _, sub := range cmd.SubCommands {
+		walk(sub, names, fn)
+	}
+}
+
+// diff returns a minimal line-by-line comparison of want and got, marking the first differing line.
+func diff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	var b strings.Builder
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			fmt.Fprintf(&b, "first difference at line %d:\n  want: %q\n  got:  %q", i+1, w, g)
+			break
+		}
+	}
+	return b.String()
+}
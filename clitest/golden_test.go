@@ -0,0 +1,77 @@
+package clitest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder captures errors reported through testing.TB so failures can be asserted on.
+type recorder struct {
+	testing.TB
+	errs []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func TestHelp(t *testing.T) {
+	t.Parallel()
+
+	root := newRoot()
+	got := Help(t, root, "add")
+	assert.Contains(t, got, "add a task")
+	assert.Contains(t, got, "--tags")
+	assert.Nil(t, root.Path(), "root should not be parsed")
+}
+
+func TestGoldenHelp(t *testing.T) {
+	t.Parallel()
+
+	t.Run("matches testdata", func(t *testing.T) {
+		t.Parallel()
+		GoldenHelp(t, newRoot(), "testdata")
+	})
+	t.Run("reports mismatch", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		for _, name := range []string{"task", "task_add", "task_import"} {
+			data, err := os.ReadFile(filepath.Join("testdata", name+".golden"))
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name+".golden"), data, 0o644))
+		}
+		root := newRoot()
+		root.SubCommands[0].ShortHelp = "add a new task"
+		rec := &recorder{TB: t}
+		GoldenHelp(rec, root, dir)
+		require.Len(t, rec.errs, 2)
+		assert.Contains(t, rec.errs[0], `help for "task"`)
+		assert.Contains(t, rec.errs[1], `help for "task add"`)
+		assert.Contains(t, rec.errs[1], `want: "add a task"`)
+		assert.Contains(t, rec.errs[1], `got:  "add a new task"`)
+	})
+	t.Run("missing golden file", func(t *testing.T) {
+		t.Parallel()
+		rec := &recorder{TB: t}
+		GoldenHelp(rec, newRoot(), t.TempDir())
+		require.Len(t, rec.errs, 3)
+		assert.Contains(t, rec.errs[0], "run with CLITEST_UPDATE=1 to create it")
+	})
+}
+
+func TestGoldenHelpUpdate(t *testing.T) {
+	// Not parallel: t.Setenv changes the process environment.
+	t.Setenv("CLITEST_UPDATE", "1")
+	dir := filepath.Join(t.TempDir(), "testdata")
+	GoldenHelp(t, newRoot(), dir)
+	want, err := os.ReadFile(filepath.Join("testdata", "task_add.golden"))
+	require.NoError(t, err)
+	got, err := os.ReadFile(filepath.Join(dir, "task_add.golden"))
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"flag"
+	"maps"
+	"slices"
+
+	"github.com/pressly/cli/flagtype"
+)
+
+// Clone returns a deep copy of the command and its subcommands, with fresh flag sets and no parse
+// state. Each clone can be parsed and run independently of the original and of other clones, which
+// makes it safe to execute the same command definition from multiple goroutines, such as parallel
+// tests or a server handling commands per request:
+//
+//	root := app.Clone()
+//	err := cli.ParseAndRun(ctx, root, args, nil)
+//
+// Flag values are copied as they are at the time of the call, so clone a definition that has not
+// been parsed. Flags defined with the Var forms, like f.StringVar(&x, ...), write to the clone's own
+// storage rather than to x; use [GetFlag] to read them. Values are copied with
+// [flagtype.CloneValue], so custom values that wrap other values or hold slices or maps need a
+// Clone method to be independent. Functions such as Exec and UsageFunc are shared, so they must be
+// safe for concurrent use themselves.
+func (c *Command) Clone() *Command {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.state = nil
+	clone.Flags = cloneFlagSet(c.Flags)
+	clone.FlagOptions = slices.Clone(c.FlagOptions)
+	for i := range clone.FlagOptions {
+		clone.FlagOptions[i].Annotations = maps.Clone(clone.FlagOptions[i].Annotations)
+	}
+	clone.Annotations = maps.Clone(c.Annotations)
+	if c.SubCommands != nil {
+		clone.SubCommands = make([]*Command, len(c.SubCommands))
+		for i, sub := range c.SubCommands {
+			clone.SubCommands[i] = sub.Clone()
+		}
+	}
+	return &clone
+}
+
+// cloneFlagSet returns a new flag set with the same name, error handling, usage function, and flags
+// as fs, where every flag has its own copy of the value.
+func cloneFlagSet(fs *flag.FlagSet) *flag.FlagSet {
+	if fs == nil {
+		return nil
+	}
+	clone := flag.NewFlagSet(fs.Name(), fs.ErrorHandling())
+	clone.Usage = fs.Usage
+	fs.VisitAll(func(f *flag.Flag) {
+		clone.Var(flagtype.CloneValue(f.Value), f.Name, f.Usage)
+		// Preserve the original default, which may differ from the value's current string form.
+		clone.Lookup(f.Name).DefValue = f.DefValue
+	})
+	return clone
+}
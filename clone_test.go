@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/pressly/cli/flagtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	newApp := func() *Command {
+		return &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("verbose", false, "verbose output")
+			}),
+			FlagOptions: []FlagOption{{Name: "verbose", Short: "v"}},
+			SubCommands: []*Command{
+				{
+					Name: "greet",
+					Flags: FlagsFunc(func(f *flag.FlagSet) {
+						f.String("name", "world", "who to greet")
+						f.Var(flagtype.StringSlice(), "tag", "tag (repeatable)")
+					}),
+					Exec: func(ctx context.Context, s *State) error {
+						_, err := fmt.Fprintf(s.Stdout, "hello %s %v %v", GetFlag[string](s, "name"),
+							GetFlag[[]string](s, "tag"), GetFlag[bool](s, "verbose"))
+						return err
+					},
+				},
+			},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+	}
+
+	t.Run("independent flag values", func(t *testing.T) {
+		t.Parallel()
+		app := newApp()
+		a, b := app.Clone(), app.Clone()
+		require.NoError(t, Parse(a, []string{"greet", "-v", "--name=alice", "--tag=x"}))
+		require.NoError(t, Parse(b, []string{"greet"}))
+
+		assert.Equal(t, "alice", GetFlag[string](a.state, "name"))
+		assert.Equal(t, []string{"x"}, GetFlag[[]string](a.state, "tag"))
+		assert.True(t, GetFlag[bool](a.state, "verbose"))
+		assert.Equal(t, "world", GetFlag[string](b.state, "name"))
+		assert.Empty(t, GetFlag[[]string](b.state, "tag"))
+		assert.False(t, GetFlag[bool](b.state, "verbose"))
+		// The original is untouched.
+		assert.Nil(t, app.Path())
+		assert.Equal(t, "world", app.SubCommands[0].Flags.Lookup("name").Value.String())
+	})
+	t.Run("independent wrapped values", func(t *testing.T) {
+		t.Parallel()
+		app := &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				noop := func(string) error { return nil }
+				f.Var(flagtype.Validate(flagtype.StringSlice(), noop), "tag", "tag (repeatable)")
+				f.Var(flagtype.Func(strconv.Atoi), "n", "a number")
+			}),
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+		a, b := app.Clone(), app.Clone()
+		require.NoError(t, Parse(a, []string{"--tag=x", "--n=1"}))
+		require.NoError(t, Parse(b, []string{"--tag=y", "--n=2"}))
+		assert.Equal(t, []string{"x"}, GetFlag[[]string](a.state, "tag"))
+		assert.Equal(t, []string{"y"}, GetFlag[[]string](b.state, "tag"))
+		assert.Equal(t, 1, GetFlag[int](a.state, "n"))
+		assert.Equal(t, 2, GetFlag[int](b.state, "n"))
+		assert.Empty(t, app.Flags.Lookup("tag").Value.String())
+	})
+	t.Run("copies definition", func(t *testing.T) {
+		t.Parallel()
+		app := newApp()
+		clone := app.Clone()
+		require.NotSame(t, app, clone)
+		require.NotSame(t, app.Flags, clone.Flags)
+		require.NotSame(t, app.SubCommands[0], clone.SubCommands[0])
+		assert.Equal(t, app.FlagOptions, clone.FlagOptions)
+		assert.Equal(t, "world", clone.SubCommands[0].Flags.Lookup("name").DefValue)
+
+		clone.FlagOptions[0].Short = "x"
+		assert.Equal(t, "v", app.FlagOptions[0].Short)
+	})
+	t.Run("copies annotations", func(t *testing.T) {
+		t.Parallel()
+		app := newApp()
+		app.Annotations = map[string]string{"group": "core"}
+		app.FlagOptions[0].Annotations = map[string]string{"docs": "verbosity"}
+		clone := app.Clone()
+		assert.Equal(t, app.Annotations, clone.Annotations)
+		assert.Equal(t, app.FlagOptions[0].Annotations, clone.FlagOptions[0].Annotations)
+
+		clone.Annotations["group"] = "extra"
+		clone.FlagOptions[0].Annotations["docs"] = "changed"
+		assert.Equal(t, "core", app.Annotations["group"])
+		assert.Equal(t, "verbosity", app.FlagOptions[0].Annotations["docs"])
+	})
+	t.Run("keeps original defaults", func(t *testing.T) {
+		t.Parallel()
+		app := newApp()
+		require.NoError(t, Parse(app, []string{"greet", "--name=bob"}))
+		clone := app.Clone()
+		f := clone.SubCommands[0].Flags.Lookup("name")
+		assert.Equal(t, "world", f.DefValue)
+		assert.Equal(t, "bob", f.Value.String())
+	})
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+		var c *Command
+		assert.Nil(t, c.Clone())
+	})
+	t.Run("concurrent runs", func(t *testing.T) {
+		t.Parallel()
+		app := newApp()
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			i := i
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var out bytes.Buffer
+				name := fmt.Sprintf("user%d", i)
+				err := ParseAndRun(context.Background(), app.Clone(), []string{"greet", "--name", name},
+					&RunOptions{Stdout: &out})
+				assert.NoError(t, err)
+				assert.Equal(t, "hello "+name+" [] false", out.String())
+			}()
+		}
+		wg.Wait()
+	})
+}
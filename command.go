@@ -2,20 +2,76 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pressly/cli/pkg/suggest"
 )
 
 // ErrHelp is returned by [Parse] when the -help or -h flag is invoked. It is identical to
 // [flag.ErrHelp] but re-exported here so callers using [Parse] and [Run] separately do not need to
-// import the flag package solely for error checking.
+// import the flag package solely for error checking. Parse wraps it in a [*HelpError] that names the
+// command whose help was requested.
 //
 // Note: [ParseAndRun] handles this automatically and never surfaces ErrHelp to the caller.
 var ErrHelp = flag.ErrHelp
 
+// HelpError is the error returned by [Parse] when help is requested. It wraps [ErrHelp], so
+// errors.Is(err, cli.ErrHelp) reports true, and carries the command resolved from the arguments so
+// callers can print the right help without walking the arguments again:
+//
+//	var helpErr *cli.HelpError
+//	if errors.As(err, &helpErr) {
+//	    fmt.Print(cli.DefaultUsage(helpErr.Command))
+//	}
+type HelpError struct {
+	// Command is the command whose help was requested, the last command in the resolved path.
+	Command *Command
+}
+
+func (e *HelpError) Error() string { return ErrHelp.Error() }
+
+// Unwrap returns [ErrHelp].
+func (e *HelpError) Unwrap() error { return ErrHelp }
+
+// ErrUsage matches every [*UsageError] with errors.Is.
+var ErrUsage = errors.New("usage error")
+
+// UsageError reports that the command line itself is wrong, as opposed to the command failing: an
+// unknown command or flag, an invalid flag value, a missing required flag, or a missing or
+// malformed positional argument. It lets main print usage and exit with code 2 only for such
+// errors:
+//
+//	if errors.Is(err, cli.ErrUsage) {
+//	    fmt.Fprintf(os.Stderr, "error: %v\n\n%s", err, cli.DefaultUsage(root))
+//	    os.Exit(2)
+//	}
+//
+// [Parse] may join several usage errors with [errors.Join]; errors.Is and errors.As still find
+// them.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the underlying error.
+func (e *UsageError) Unwrap() error { return e.Err }
+
+// Is reports whether target is [ErrUsage].
+func (e *UsageError) Is(target error) bool { return target == ErrUsage }
+
+// ExitCode returns 2, the conventional exit code for command line usage errors.
+func (e *UsageError) ExitCode() int { return 2 }
+
+// usageErrorf is like [fmt.Errorf] but returns a [*UsageError].
+func usageErrorf(format string, args ...any) error {
+	return &UsageError{Err: fmt.Errorf(format, args...)}
+}
+
 // Command represents a CLI command or subcommand within the application's command hierarchy.
 type Command struct {
 	// Name is always a single word representing the command's name. It is used to identify the
@@ -25,17 +81,43 @@ type Command struct {
 	// Usage provides the command's full usage pattern.
 	//
 	// Example: "cli todo list [flags]"
+	//
+	// If empty, the usage is generated from the command path, "<command>" if the command has
+	// subcommands, ArgsUsage, and "[flags]", like "todo task add <text> [flags]".
 	Usage string
 
+	// ArgsUsage describes the command's positional arguments in the generated usage, like "<text>"
+	// or "<id> [reason]". It is ignored when Usage is set.
+	ArgsUsage string
+
 	// ShortHelp is a brief description of the command's purpose. It is displayed in the help text
 	// when the command is shown.
 	ShortHelp string
 
+	// LongHelp is a detailed description of the command, shown in place of ShortHelp at the top of
+	// the command's own help. It may use lightweight markdown: **bold** and `code` spans, bullet
+	// lists, and code blocks. [DefaultUsage] renders it as plain text for the terminal (see
+	// textutil.RenderMarkdown), while [HelpJSON] passes it through as written for documentation
+	// generators.
+	LongHelp string
+
+	// Example holds example invocations, shown line by line in an Examples section of the help.
+	// It may be written as a markdown code block, fenced with ```, in which case [DefaultUsage]
+	// drops the fence lines and [HelpJSON] passes it through as written.
+	Example string
+
 	// UsageFunc is an optional function that can be used to generate a custom usage string for the
 	// command. It receives the current command and should return a string with the full usage
 	// pattern.
 	UsageFunc func(*Command) string
 
+	// HelpHeader and HelpFooter are printed by [DefaultUsage] before and after the standard help
+	// sections, for banners, links to documentation, or "report bugs to" lines. A command without
+	// its own header or footer uses the nearest one set on an ancestor, so setting them on the root
+	// command applies them to every subcommand.
+	HelpHeader string
+	HelpFooter string
+
 	// Flags holds the command-specific flag definitions. Each command maintains its own flag set
 	// for parsing arguments.
 	Flags *flag.FlagSet
@@ -43,9 +125,86 @@ type Command struct {
 	// behavior. This is useful for tracking required flags, short aliases, and local flags.
 	FlagOptions []FlagOption
 
+	// FlagsStopAtFirstArg stops flag parsing at the command's first positional argument. That
+	// argument and everything after it, including flags and "--", are passed through untouched in
+	// [State].Args. This suits wrapper commands that forward arguments to another program, like
+	// "app run [flags] <image> [args...]", without requiring users to type "--".
+	//
+	// By default, flags are parsed anywhere in the arguments.
+	FlagsStopAtFirstArg bool
+
+	// FlagsLocalByDefault makes the command's flags local, as if every flag had Local set in its
+	// [FlagOption], except those marked Persistent. This mirrors the local and persistent flags of
+	// frameworks like cobra: set it on the root command and mark the flags meant for every
+	// subcommand, like --verbose or --config, as Persistent.
+	//
+	// By default, every flag is inherited by child commands unless marked Local.
+	FlagsLocalByDefault bool
+
+	// AllowUnknownFlags collects flags that are not defined on the command (or inherited from its
+	// ancestors) into [State].Args instead of returning an error. Unknown flags keep their position
+	// relative to positional arguments, which suits proxy commands that forward arbitrary flags to
+	// another tool. Values of unknown flags are only kept together with the flag when written inline,
+	// like --unknown=value.
+	AllowUnknownFlags bool
+
+	// DisableArgFiles turns off "@path" argument file expansion in [Parse]. Only consulted on the
+	// root command.
+	DisableArgFiles bool
+
+	// PreParse, if set, rewrites the arguments before [Parse] resolves commands and flags. It
+	// receives the arguments exactly as passed to Parse, before "@path" argument files are expanded,
+	// and returns the arguments to parse instead. Use it for user-defined command aliases (like
+	// "co" for "checkout"), custom argument expansion, or recording the raw invocation. Returning an
+	// error stops parsing. Only consulted on the root command.
+	PreParse func(args []string) ([]string, error)
+
+	// DisallowFlagShadowing makes [Parse] return an error when a command defines a flag with the
+	// same name as one it inherits from an ancestor. By default the child's flag silently takes
+	// precedence. Only consulted on the root command, and applies to the whole command tree.
+	DisallowFlagShadowing bool
+
+	// PrefixMatching lets users abbreviate subcommands to any unique prefix of their name, so
+	// "todo l" runs "todo list" when no other subcommand of todo starts with "l". An ambiguous
+	// prefix is an error listing the candidates. Exact names always win, so "todo list" still works
+	// alongside a "listen" command. Only consulted on the root command.
+	PrefixMatching bool
+
+	// MatchCase sets whether command and flag names typed by the user must match their case. See
+	// [MatchCase] for the choices. Only consulted on the root command.
+	MatchCase MatchCase
+
+	// SuggestDepth is how many levels of subcommands are searched for suggestions when a command
+	// is unknown. The default, 1, only suggests direct subcommands; with 2, "todo overdue" also
+	// suggests "list overdue" for a "todo list overdue" command. Only consulted on the root
+	// command.
+	SuggestDepth int
+
+	// HelpFlag is the name of the flag that requests help for the command, without dashes. Defaults
+	// to "help". The help flag is listed in the Flags section of [DefaultUsage].
+	HelpFlag string
+	// HelpShort is the single-letter alias of the help flag. Defaults to "h".
+	//
+	// The help flag and its alias give way to any flag or short alias the command defines (or
+	// inherits) with the same name, so an application can use -h for --host without further setup.
+	HelpShort string
+	// DisableHelpFlag turns off the automatic help flag for the command. Help flags are then parsed
+	// like any other argument and [Parse] never returns [ErrHelp] for it.
+	DisableHelpFlag bool
+
+	// Annotations holds arbitrary key-value metadata about the command. The package itself ignores
+	// it; it is an extension point for custom usage functions, documentation generators, and other
+	// tooling that walks the command tree.
+	Annotations map[string]string
+
 	// SubCommands is a list of nested commands that exist under this command.
 	SubCommands []*Command
 
+	// Timeout limits how long the command's Exec function may run. When set, [Run] passes Exec a
+	// context with this deadline, and an error returned after the deadline passed is wrapped with
+	// [ErrTimeout]. Exec must honor context cancellation for the timeout to take effect.
+	Timeout time.Duration
+
 	// Exec defines the command's execution logic. It receives the current application [State] and
 	// returns an error if execution fails. This function is called when [Run] is invoked on the
 	// command.
@@ -54,8 +213,31 @@ type Command struct {
 	state *State
 }
 
+// MatchCase is the policy for matching the case of command and flag names, set with
+// [Command].MatchCase.
+type MatchCase int
+
+const (
+	// MatchCaseDefault matches command names ignoring case, so "todo LIST" runs "todo list", and
+	// flag names exactly, like the flag package.
+	MatchCaseDefault MatchCase = iota
+	// MatchCaseSensitive matches command and flag names exactly.
+	MatchCaseSensitive
+	// MatchCaseInsensitive matches command and flag names ignoring case, so --Verbose sets
+	// --verbose. A flag name that matches several flags when ignoring case, like -V for -v and -V,
+	// must match exactly.
+	MatchCaseInsensitive
+)
+
+// foldCommands reports whether command names are matched ignoring case.
+func (m MatchCase) foldCommands() bool { return m != MatchCaseSensitive }
+
+// foldFlags reports whether flag names are matched ignoring case.
+func (m MatchCase) foldFlags() bool { return m == MatchCaseInsensitive }
+
 // Path returns the command chain from root to current command. It can only be called after the root
-// command has been parsed and the command hierarchy has been established.
+// command has been parsed and the command hierarchy has been established. Commands in the resolved
+// path share the root's parse state, so Path may be called on any of them.
 func (c *Command) Path() []*Command {
 	if c.state == nil {
 		return nil
@@ -87,6 +269,40 @@ type FlagOption struct {
 	// Local indicates that the flag should not be inherited by child commands. When true, the flag
 	// is only available on the command that defines it.
 	Local bool
+
+	// Persistent marks the flag as inherited by child commands when the command sets
+	// FlagsLocalByDefault. It cannot be combined with Local.
+	Persistent bool
+
+	// Negatable registers a --no-<name> form for a boolean flag, so users can write --no-color
+	// instead of --color=false. Both forms are shown in help output as --[no-]color. Only valid for
+	// boolean flags.
+	Negatable bool
+
+	// Section groups the flag under its own heading in help output, like "Connection Flags", instead
+	// of the default Flags section. Sections are listed after the Flags section, in the order they
+	// first appear in FlagOptions. Inherited flags are always shown under Inherited Flags.
+	Section string
+
+	// Hidden leaves the flag out of help output while it keeps parsing normally. Use it for internal
+	// or experimental flags. Passing --help-all (the help flag name with an "-all" suffix) shows
+	// help with hidden flags included.
+	Hidden bool
+
+	// Sensitive marks the flag's value as secret, like a password or API token. The value is
+	// masked wherever the package prints it: the default value in help output, values passed to
+	// [State.VisitFlags], and parse errors that would echo it. Use it together with a value type
+	// like flagtype.Secret, which also masks the value in the application's own logs.
+	Sensitive bool
+
+	// EnvVar names an environment variable that supplies the flag's value when the flag is not given
+	// on the command line, like "APP_PORT". The variable is shown in help output, and a value from
+	// it satisfies Required.
+	EnvVar string
+
+	// Annotations holds arbitrary key-value metadata about the flag, for the same tooling as
+	// [Command].Annotations.
+	Annotations map[string]string
 }
 
 // FlagsFunc is a helper function that creates a new [flag.FlagSet] and applies the given function
@@ -104,28 +320,130 @@ func FlagsFunc(fn func(f *flag.FlagSet)) *flag.FlagSet {
 }
 
 // findSubCommand searches for a subcommand by name and returns it if found. Returns nil if no
-// subcommand with the given name exists.
-func (c *Command) findSubCommand(name string) *Command {
+// subcommand with the given name exists. Names are compared ignoring case if fold is true.
+func (c *Command) findSubCommand(name string, fold bool) *Command {
 	for _, sub := range c.SubCommands {
-		if strings.EqualFold(sub.Name, name) {
+		if equalName(sub.Name, name, fold) {
 			return sub
 		}
 	}
 	return nil
 }
 
-func (c *Command) formatUnknownCommandError(unknownCmd string) error {
-	var known []string
+// equalName reports whether a and b are the same name, ignoring case if fold is true.
+func equalName(a, b string, fold bool) bool {
+	if fold {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// findSubCommandByPrefix returns the only subcommand whose name starts with prefix. It returns
+// nil if there is none, and an error listing the candidates if there are several.
+func (c *Command) findSubCommandByPrefix(prefix string, fold bool) (*Command, error) {
+	if prefix == "" {
+		return nil, nil
+	}
+	var matches []*Command
 	for _, sub := range c.SubCommands {
-		known = append(known, sub.Name)
+		if len(sub.Name) >= len(prefix) && equalName(sub.Name[:len(prefix)], prefix, fold) {
+			matches = append(matches, sub)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, sub := range matches {
+		names[i] = sub.Name
+	}
+	return nil, usageErrorf(tr("ambiguous command %q. It matches all of these:\n\t%s"),
+		prefix,
+		strings.Join(names, "\n\t"))
+}
+
+// formatUnknownCommandError returns the error for an unknown subcommand of c, suggesting similar
+// commands up to depth levels below c by their path relative to c, like "list overdue".
+func (c *Command) formatUnknownCommandError(unknownCmd string, depth int) error {
+	var known []string
+	paths := make(map[string][]string) // name -> relative paths, shallowest first
+	level := []*Command{c}
+	prefixes := []string{""}
+	for d := 0; d < max(depth, 1) && len(level) > 0; d++ {
+		var next []*Command
+		var nextPrefixes []string
+		for i, cmd := range level {
+			for _, sub := range cmd.SubCommands {
+				if _, ok := paths[sub.Name]; !ok {
+					known = append(known, sub.Name)
+				}
+				path := prefixes[i] + sub.Name
+				paths[sub.Name] = append(paths[sub.Name], path)
+				next = append(next, sub)
+				nextPrefixes = append(nextPrefixes, path+" ")
+			}
+		}
+		level, prefixes = next, nextPrefixes
+	}
+	var suggestions []string
+	for _, name := range suggest.FindSimilar(unknownCmd, known, 3) {
+		suggestions = append(suggestions, paths[name]...)
+	}
+	if len(suggestions) > 3 {
+		suggestions = suggestions[:3]
 	}
-	suggestions := suggest.FindSimilar(unknownCmd, known, 3)
 	if len(suggestions) > 0 {
-		return fmt.Errorf("unknown command %q. Did you mean one of these?\n\t%s",
+		return usageErrorf(tr("unknown command %q. Did you mean one of these?\n\t%s"),
 			unknownCmd,
 			strings.Join(suggestions, "\n\t"))
 	}
-	return fmt.Errorf("unknown command %q", unknownCmd)
+	return usageErrorf(tr("unknown command %q"), unknownCmd)
+}
+
+// helpFlagNames returns the long name and short alias of the command's help flag. Either is "" when
+// the help flag is disabled or the name is taken by a flag in fs.
+func (c *Command) helpFlagNames(fs *flag.FlagSet) (long, short string) {
+	if c.DisableHelpFlag {
+		return "", ""
+	}
+	long, short = "help", "h"
+	if c.HelpFlag != "" {
+		long = c.HelpFlag
+	}
+	if c.HelpShort != "" {
+		short = c.HelpShort
+	}
+	if fs.Lookup(long) != nil {
+		long = ""
+	}
+	if fs.Lookup(short) != nil {
+		short = ""
+	}
+	return long, short
+}
+
+// helpAllFlagName returns the name of the flag that requests help including hidden flags, derived
+// from the long help flag name. It returns "" when there is no long help flag or the name is taken
+// by a flag in fs.
+func helpAllFlagName(long string, fs *flag.FlagSet) string {
+	if long == "" || fs.Lookup(long+"-all") != nil {
+		return ""
+	}
+	return long + "-all"
+}
+
+// isHelpArg reports whether arg requests help given the help flag names from helpFlagNames. Both
+// names are accepted with one or two dashes.
+func isHelpArg(arg, long, short string) bool {
+	name, ok := strings.CutPrefix(arg, "-")
+	if !ok {
+		return false
+	}
+	name = strings.TrimPrefix(name, "-")
+	return name != "" && (name == long || name == short)
 }
 
 func formatFlagName(name string) string {
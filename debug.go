@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// debugEnvVar is the environment variable that turns on parse tracing when set to a true value, like
+// CLI_DEBUG=1. See [RunOptions].DebugOutput.
+const debugEnvVar = "CLI_DEBUG"
+
+// debugOutput returns the writer for parse traces: out if set, otherwise stderr when CLI_DEBUG is
+// set to a true value, otherwise nil.
+func debugOutput(out io.Writer, lookupEnv func(string) (string, bool), stderr io.Writer) io.Writer {
+	if out != nil {
+		return out
+	}
+	if v, ok := lookupEnv(debugEnvVar); ok {
+		if on, err := strconv.ParseBool(v); err == nil && on {
+			return stderr
+		}
+	}
+	return nil
+}
+
+// debugf writes a trace line if tracing is on.
+func (c parseConfig) debugf(format string, args ...any) {
+	if c.debug == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(c.debug, "cli: "+format+"\n", args...)
+}
+
+// traceFlags writes the final value of every flag available to the terminal command, with the
+// command that defines it and where the value came from. fromArgs holds the flags set on the
+// command line, and fromEnv maps flag names to the environment variables they were set from.
+func (c parseConfig) traceFlags(s *State, fromArgs map[string]bool, fromEnv map[string]string) {
+	if c.debug == nil {
+		return
+	}
+	owner := make(map[string]string)
+	terminalIdx := len(s.path) - 1
+	for i := terminalIdx; i >= 0; i-- {
+		cmd := s.path[i]
+		if cmd.Flags == nil {
+			continue
+		}
+		local := localFlagSet(cmd)
+		cmd.Flags.VisitAll(func(f *flag.Flag) {
+			if _, ok := owner[f.Name]; !ok && (i == terminalIdx || !local[f.Name]) {
+				owner[f.Name] = getCommandPath(s.path[:i+1])
+			}
+		})
+	}
+	s.VisitFlags(func(name string, value flag.Value, _ FlagOption) {
+		source := "default"
+		if env, ok := fromEnv[name]; ok {
+			source = "set by environment variable " + env
+		} else if fromArgs[name] {
+			source = "set on command line"
+		}
+		c.debugf("flag -%s=%q (defined on %q, %s)", name, value.String(), owner[name], source)
+	})
+}
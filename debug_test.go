@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugOutput(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func() *Command {
+		return &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("verbose", false, "verbose output")
+				f.String("region", "us", "cloud region")
+				f.String("token", "", "API token")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "verbose", Short: "v"},
+				{Name: "region", EnvVar: "APP_REGION"},
+				{Name: "token", Sensitive: true},
+			},
+			SubCommands: []*Command{{
+				Name: "deploy",
+				Flags: FlagsFunc(func(f *flag.FlagSet) {
+					f.Bool("dry-run", false, "print the plan only")
+				}),
+				Exec: func(ctx context.Context, s *State) error { return nil },
+			}},
+		}
+	}
+	env := map[string]string{"APP_REGION": "eu"}
+	lookupEnv := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	t.Run("debug output", func(t *testing.T) {
+		t.Parallel()
+		var debug bytes.Buffer
+		err := ParseAndRun(context.Background(), newRoot(), []string{"deploy", "-v", "--token=abc"}, &RunOptions{
+			DebugOutput: &debug,
+			LookupEnv:   lookupEnv,
+		})
+		require.NoError(t, err)
+		want := `cli: resolved command "app deploy"
+cli: flag -dry-run="false" (defined on "app deploy", default)
+cli: flag -region="eu" (defined on "app", set by environment variable APP_REGION)
+cli: flag -token="*****" (defined on "app", set on command line)
+cli: flag -verbose="true" (defined on "app", set on command line)
+`
+		assert.Equal(t, want, debug.String())
+	})
+	t.Run("environment variable", func(t *testing.T) {
+		t.Parallel()
+		var stderr bytes.Buffer
+		err := ParseAndRun(context.Background(), newRoot(), []string{"deploy", "--help"}, &RunOptions{
+			Stdout: &bytes.Buffer{},
+			Stderr: &stderr,
+			LookupEnv: func(key string) (string, bool) {
+				if key == "CLI_DEBUG" {
+					return "1", true
+				}
+				return "", false
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "cli: resolved command \"app deploy\"\ncli: help requested with --help\n", stderr.String())
+	})
+	t.Run("off by default", func(t *testing.T) {
+		t.Parallel()
+		var stderr bytes.Buffer
+		err := ParseAndRun(context.Background(), newRoot(), []string{"deploy"}, &RunOptions{
+			Stderr:    &stderr,
+			LookupEnv: lookupEnv,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, stderr.String())
+	})
+}
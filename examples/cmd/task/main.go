@@ -1,27 +1,29 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pressly/cli"
+	"github.com/pressly/cli/flagtype"
+	"github.com/pressly/cli/pkg/tablewriter"
 )
 
 func main() {
 	root := &cli.Command{
 		Name:      "todo",
-		Usage:     "todo <command> [flags]",
 		ShortHelp: "A simple CLI for managing your tasks",
 		Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
 			f.Bool("verbose", false, "enable verbose output")
 			f.Bool("version", false, "print the version")
+			cli.PromptFlags(f)
 		}),
 		Exec: func(ctx context.Context, s *cli.State) error {
 			if cli.GetFlag[bool](s, "version") {
@@ -46,7 +48,6 @@ func main() {
 func list() *cli.Command {
 	return &cli.Command{
 		Name:      "list",
-		Usage:     "todo list <command> [flags]",
 		ShortHelp: "List tasks",
 		Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
 			f.String("file", "", "path to the tasks file")
@@ -66,6 +67,21 @@ func list() *cli.Command {
 	}
 }
 
+func printTasks(w io.Writer, tasks []Task) error {
+	table := tablewriter.New(w)
+	table.SetHeader("ID", "TEXT", "CREATED", "STATUS", "TAGS")
+	for _, t := range tasks {
+		table.Append(
+			strconv.Itoa(t.ID),
+			t.Text,
+			t.Created.Format("2006-01-02"),
+			string(t.Status),
+			strings.Join(t.Tags, ","),
+		)
+	}
+	return table.Render()
+}
+
 func getTasksFromFile(s *cli.State) (*TaskList, error) {
 	file := cli.GetFlag[string](s, "file")
 	return Load(file)
@@ -74,7 +90,6 @@ func getTasksFromFile(s *cli.State) (*TaskList, error) {
 func listToday() *cli.Command {
 	return &cli.Command{
 		Name:      "today",
-		Usage:     "todo list today [flags]",
 		ShortHelp: "List tasks due today",
 		Exec: func(ctx context.Context, s *cli.State) error {
 			tasks, err := getTasksFromFile(s)
@@ -87,10 +102,7 @@ func listToday() *cli.Command {
 				return nil
 			}
 			fmt.Fprintf(s.Stdout, "Tasks due today:\n")
-			for _, task := range today {
-				fmt.Fprintf(s.Stdout, "  %s\n", task.String())
-			}
-			return nil
+			return printTasks(s.Stdout, today)
 		},
 	}
 }
@@ -98,7 +110,6 @@ func listToday() *cli.Command {
 func listOverdue() *cli.Command {
 	return &cli.Command{
 		Name:      "overdue",
-		Usage:     "todo list overdue [flags]",
 		ShortHelp: "List overdue tasks",
 		Exec: func(ctx context.Context, s *cli.State) error {
 			tasks, err := getTasksFromFile(s)
@@ -111,18 +122,14 @@ func listOverdue() *cli.Command {
 				return nil
 			}
 			fmt.Fprintf(s.Stdout, "Overdue tasks:\n")
-			for _, task := range overdue {
-				fmt.Fprintf(s.Stdout, "  %s\n", task.String())
-			}
-			return nil
+			return printTasks(s.Stdout, overdue)
 		},
 	}
 }
 
 func task() *cli.Command {
 	return &cli.Command{
-		Name:  "task",
-		Usage: "todo task <command> [flags]",
+		Name: "task",
 		Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
 			f.String("file", "", "path to the tasks file")
 		}),
@@ -141,20 +148,16 @@ func task() *cli.Command {
 func taskAdd() *cli.Command {
 	return &cli.Command{
 		Name:      "add",
-		Usage:     "todo task add <text> [flags]",
+		ArgsUsage: "<text>",
 		ShortHelp: "Add a new task",
 		Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
-			f.String("tags", "", "comma-separated list of tags")
+			f.Var(flagtype.StringSliceDelim(","), "tags", "comma-separated list of tags (repeatable)")
 		}),
 		Exec: func(ctx context.Context, s *cli.State) error {
 			var (
-				tagsText = cli.GetFlag[string](s, "tags")
-				file     = cli.GetFlag[string](s, "file")
+				tags = cli.GetFlag[[]string](s, "tags")
+				file = cli.GetFlag[string](s, "file")
 			)
-			var tags []string
-			if tagsText != "" {
-				tags = strings.Split(tagsText, ",")
-			}
 			tasks, err := getTasksFromFile(s)
 			if err != nil {
 				return err
@@ -180,22 +183,18 @@ func taskAdd() *cli.Command {
 func taskDone() *cli.Command {
 	return &cli.Command{
 		Name:      "done",
-		Usage:     "todo task done <id> [flags]",
+		ArgsUsage: "<id>",
 		ShortHelp: "Mark a task as done",
 		Exec: func(ctx context.Context, s *cli.State) error {
-			if len(s.Args) == 0 {
-				return errors.New("task ID required")
+			id, err := s.ArgInt(0)
+			if err != nil {
+				return fmt.Errorf("task ID: %w", err)
 			}
 			tasks, err := getTasksFromFile(s)
 			if err != nil {
 				return err
 			}
-			id := s.Args[0]
-			parsedID, err := strconv.Atoi(id)
-			if err != nil {
-				return fmt.Errorf("invalid task ID: %w", err)
-			}
-			return tasks.Done(parsedID)
+			return tasks.Done(id)
 		},
 	}
 }
@@ -203,7 +202,7 @@ func taskDone() *cli.Command {
 func taskRemove() *cli.Command {
 	return &cli.Command{
 		Name:      "remove",
-		Usage:     "todo task remove <id> [flags]",
+		ArgsUsage: "<id>",
 		ShortHelp: "Remove a task",
 		Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
 			f.Bool("force", false, "force removal without confirmation")
@@ -220,20 +219,15 @@ func taskRemove() *cli.Command {
 			}
 			if all {
 				if !force {
-
-					reader := bufio.NewReader(os.Stdin)
-					fmt.Print("Are you sure you want to clear all tasks? (y/N): ")
-					response, err := reader.ReadString('\n')
+					ok, err := cli.Confirm(s, "Are you sure you want to clear all tasks?", false)
 					if err != nil {
-						return fmt.Errorf("failed to read input: %w", err)
+						return err
 					}
-					response = strings.TrimSpace(strings.ToLower(response))
-					if response != "y" {
+					if !ok {
 						fmt.Fprintf(s.Stdout, "Operation cancelled\n")
 						return nil
 					}
 				}
-				// add a confirmation prompt
 				return Save(file, &TaskList{})
 			}
 			return nil
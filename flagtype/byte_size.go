@@ -0,0 +1,113 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type byteSizeValue struct {
+	n int64
+}
+
+// ByteSize returns a [flag.Value] that parses a human-readable size into a number of bytes. Both SI
+// units (KB, MB, GB, TB, PB, EB as powers of 1000) and IEC units (KiB, MiB, GiB, TiB, PiB, EiB as
+// powers of 1024) are accepted, case-insensitively, as are plain byte counts and fractional values:
+// "512", "512B", "64KB", "10MiB", "1.5GB". Negative sizes are rejected.
+//
+// Use [cli.GetFlag] with type int64 to retrieve the value.
+func ByteSize() flag.Value {
+	return &byteSizeValue{}
+}
+
+func (v *byteSizeValue) String() string {
+	return formatByteSize(v.n)
+}
+
+func (v *byteSizeValue) Set(s string) error {
+	n, err := parseByteSize(s)
+	if err != nil {
+		return err
+	}
+	v.n = n
+	return nil
+}
+
+func (v *byteSizeValue) Get() any {
+	return v.n
+}
+
+// byteUnits lists the supported units from largest to smallest, IEC before SI so formatting prefers
+// the binary unit when a size is a whole multiple of both.
+var byteUnits = []struct {
+	name string
+	size int64
+}{
+	{"EiB", 1 << 60}, {"EB", 1e18},
+	{"PiB", 1 << 50}, {"PB", 1e15},
+	{"TiB", 1 << 40}, {"TB", 1e12},
+	{"GiB", 1 << 30}, {"GB", 1e9},
+	{"MiB", 1 << 20}, {"MB", 1e6},
+	{"KiB", 1 << 10}, {"KB", 1e3},
+	{"B", 1},
+}
+
+func parseByteSize(s string) (int64, error) {
+	trimmed := strings.TrimSpace(s)
+	// Split the number from the unit at the first character that can't be part of a number.
+	i := strings.IndexFunc(trimmed, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	num, unit := trimmed, ""
+	if i >= 0 {
+		num, unit = trimmed[:i], strings.TrimSpace(trimmed[i:])
+	}
+	if num == "" {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	multiplier := int64(1)
+	if unit != "" {
+		found := false
+		for _, u := range byteUnits {
+			if strings.EqualFold(unit, u.name) {
+				multiplier, found = u.size, true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("invalid size %q: unknown unit %q", s, unit)
+		}
+	}
+	// Parse whole numbers exactly, falling back to floating point for fractional values.
+	if n, err := strconv.ParseInt(num, 10, 64); err == nil {
+		if n > math.MaxInt64/multiplier {
+			return 0, fmt.Errorf("invalid size %q: too large", s)
+		}
+		return n * multiplier, nil
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	size := f * float64(multiplier)
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid size %q: too large", s)
+	}
+	return int64(size), nil
+}
+
+// formatByteSize formats n with the largest unit that divides it exactly, so the result can be
+// parsed back to the same value.
+func formatByteSize(n int64) string {
+	if n == 0 {
+		return "0"
+	}
+	for _, u := range byteUnits {
+		if n%u.size == 0 {
+			return strconv.FormatInt(n/u.size, 10) + u.name
+		}
+	}
+	return strconv.FormatInt(n, 10) + "B"
+}
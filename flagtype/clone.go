@@ -0,0 +1,31 @@
+package flagtype
+
+import (
+	"flag"
+	"reflect"
+)
+
+// CloneValue returns a copy of v that does not share storage with it, so setting one does not
+// change the other. If v has a Clone method returning a [flag.Value], CloneValue uses it; the
+// wrapper, slice, and map values in this package all have one, and custom values that hold
+// pointers, slices, maps, or other flag values should add one too.
+//
+// Otherwise, values are almost always pointers to the actual storage (a *string for f.String, a
+// struct pointer for most custom types), so CloneValue copies the pointed-to value. Non-pointer
+// values, like the function types behind f.Func, hold no state of their own and are returned
+// as-is.
+func CloneValue(v flag.Value) flag.Value {
+	if c, ok := v.(interface{ Clone() flag.Value }); ok {
+		return c.Clone()
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return v
+	}
+	copied := reflect.New(rv.Elem().Type())
+	copied.Elem().Set(rv.Elem())
+	if cv, ok := copied.Interface().(flag.Value); ok {
+		return cv
+	}
+	return v
+}
@@ -0,0 +1,40 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+)
+
+type countValue struct {
+	n int
+}
+
+// Count returns a [flag.Value] that counts how many times the flag is provided, like -v -v -v for
+// increasing verbosity. It behaves like a boolean flag, so it never consumes the following
+// argument. When registered with a short alias in [cli.FlagOption], the alias may be repeated in a
+// single argument, so -vvv is equivalent to -v -v -v.
+//
+// Use [cli.GetFlag] with type int to retrieve the value.
+func Count() flag.Value {
+	return &countValue{}
+}
+
+func (v *countValue) String() string {
+	return fmt.Sprint(v.n)
+}
+
+func (v *countValue) Set(s string) error {
+	if s != "true" {
+		return fmt.Errorf("count flag does not take a value, got %q", s)
+	}
+	v.n++
+	return nil
+}
+
+func (v *countValue) Get() any {
+	return v.n
+}
+
+func (v *countValue) IsBoolFlag() bool {
+	return true
+}
@@ -6,9 +6,54 @@
 //   - [StringSlice] - repeatable flag that collects values into []string
 //   - [Enum] - restricts values to a predefined set, retrieved as string
 //   - [EnumDefault] - like [Enum] but with an initial default value
+//   - [EnumWith] - like [Enum] but with case-insensitive matching or aliases like yml for yaml
 //   - [StringMap] - repeatable flag that parses key=value pairs into map[string]string
 //   - [URL] - parses and validates a URL (must have scheme and host), retrieved as *url.URL
+//   - [URLScheme] - like [URL] but restricted to the given schemes, retrieved as *url.URL
+//   - [URLSlice] - repeatable flag that collects URLs, retrieved as []*url.URL
 //   - [Regexp] - compiles a regular expression, retrieved as *regexp.Regexp
+//   - [RegexpSlice] - repeatable flag that compiles each pattern, retrieved as []*regexp.Regexp
+//   - [Count] - counts repeated occurrences like -v -v -v (or -vvv), retrieved as int
+//   - [IntSlice], [Int64Slice], [Float64Slice] - repeatable numeric flags collected into slices
+//   - [DurationSlice] - repeatable flag that collects values into []time.Duration
+//   - [DurationRange] - duration validated against inclusive bounds, retrieved as time.Duration
+//   - [IntRange], [Float64Range] - numbers validated against inclusive bounds, as int or float64
+//   - [ByteSize] - parses sizes like 512KB or 10MiB (SI and IEC units), retrieved as int64 bytes
+//   - [Time] - parses RFC3339 (or custom layouts) and relative times like now-24h, as time.Time
+//   - [IP] - parses an IPv4 or IPv6 address, retrieved as netip.Addr
+//   - [CIDR] - parses an IP network in CIDR notation, retrieved as netip.Prefix
+//   - [HostPort] - parses host:port (including [::1]:8080), retrieved as [HostAndPort]
+//   - [Port] - parses a port number between 1 and 65535, retrieved as int
+//   - [Path] - converts the value to a clean absolute path, retrieved as string
+//   - [ExistingFile], [ExistingDir] - require an existing file or directory, retrieved as string
+//   - [FileContents] - reads the named file (or stdin for "-") at parse time, retrieved as []byte
+//   - [JSON] - parses a JSON object, retrieved as map[string]any
+//   - [JSONInto] - unmarshals JSON into a typed value, retrieved as T
+//   - [UUID] - validates a UUID with or without dashes, retrieved as a normalized string
+//   - [Semver] - parses a semantic version (optional leading v), retrieved as [Version]
+//   - [EnumSlice] - repeatable flag restricted to a predefined set, retrieved as []string
+//   - [LogLevel] - parses debug, info, warn, or error (or a number), retrieved as slog.Level
+//   - [Timezone] - loads a time zone like America/New_York, retrieved as *time.Location
+//   - [FileMode] - parses octal permissions like 0644, retrieved as os.FileMode
+//   - [KeyPair] - loads a PEM certificate and key from cert,key paths, retrieved as *tls.Certificate
+//   - [Base64], [Hex] - decode base64 or hex values at parse time, retrieved as []byte
+//   - [Template] - compiles a text/template at parse time, retrieved as *template.Template
+//   - [Glob] - validates a glob pattern (with ** for any depth), retrieved as string
+//   - [GlobFiles] - like [Glob] but expands the pattern at parse time, retrieved as []string
+//   - [StringSliceDelim] - like [StringSlice] but splits each occurrence on a separator
+//   - [KeyValueSlice] - repeatable key=value flag keeping order and duplicates, retrieved as []KV
+//   - [StringMultiMap] - like [StringMap] but repeated keys collect values, as map[string][]string
+//   - [Secret] - sensitive value that prints as *****, retrieved as [SecretString]
+//   - [SecretSource] - resolves env://NAME, file:///path, or a literal secret, as [SecretString]
+//   - [ExpandedString] - expands $VAR and ${VAR} from the environment, retrieved as string
+//
+// Enum and range types, like [Enum], [EnumSlice], [IntRange], [DurationRange], and [Port], also
+// have a Describe method that [cli.DefaultUsage] uses to show their allowed values or bounds in
+// help output.
+//
+// To build a custom type without implementing [flag.Value], use [Func] with a parse function, or
+// wrap any value with [Validate] to add a check on the raw input. Custom values that hold slices,
+// maps, or other flag values should have a Clone method so [CloneValue] can copy them.
 //
 // Example registration:
 //
@@ -0,0 +1,76 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type durationSliceValue struct {
+	sliceValue[time.Duration]
+}
+
+// DurationSlice returns a [flag.Value] that collects durations into a slice. Each time the flag is
+// set, the value is parsed with [time.ParseDuration] and appended, like --retry-after=1s
+// --retry-after=5s. Use [WithSeparator] to also accept several values per occurrence.
+//
+// Use [cli.GetFlag] with type []time.Duration to retrieve the value.
+func DurationSlice(opts ...SliceOption) flag.Value {
+	return &durationSliceValue{newSliceValue(parseDuration, time.Duration.String, opts)}
+}
+
+type durationValue struct {
+	d        time.Duration
+	min, max time.Duration
+}
+
+// DurationRange returns a [flag.Value] that parses a duration with [time.ParseDuration] and rejects
+// values outside [min, max] (inclusive) at parse time, so bounds don't need to be checked in every
+// Exec function. The value starts at zero, which is not checked against the bounds. DurationRange
+// panics if min is greater than max.
+//
+// Use [cli.GetFlag] with type time.Duration to retrieve the value.
+func DurationRange(min, max time.Duration) flag.Value {
+	if min > max {
+		panic(fmt.Sprintf("flagtype: duration range minimum %v is greater than maximum %v", min, max))
+	}
+	return &durationValue{min: min, max: max}
+}
+
+func (v *durationValue) String() string {
+	return v.d.String()
+}
+
+func (v *durationValue) Set(s string) error {
+	d, err := parseDuration(s)
+	if err != nil {
+		return err
+	}
+	if d < v.min || d > v.max {
+		return fmt.Errorf("invalid duration %q, must be between %v and %v", s, v.min, v.max)
+	}
+	v.d = d
+	return nil
+}
+
+func (v *durationValue) Get() any {
+	return v.d
+}
+
+// Describe shows the bounds for help output.
+func (v *durationValue) Describe() string {
+	return fmt.Sprintf("range: %v-%v", v.min, v.max)
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+func (v *durationSliceValue) Clone() flag.Value {
+	return &durationSliceValue{v.sliceValue.clone()}
+}
@@ -0,0 +1,76 @@
+package flagtype
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+type bytesValue struct {
+	data   []byte
+	decode func(string) ([]byte, error)
+}
+
+// Base64 returns a [flag.Value] that decodes a base64 value at parse time. Standard and URL-safe
+// alphabets are accepted, with or without padding. Because such values are often secrets, decoding
+// errors describe the problem without repeating the value, and String returns "", so a default set
+// before the flag is registered is not shown in help output.
+//
+// Use [cli.GetFlag] with type []byte to retrieve the decoded bytes.
+func Base64() flag.Value {
+	return &bytesValue{decode: decodeBase64}
+}
+
+// Hex returns a [flag.Value] that decodes a hexadecimal value, with an optional 0x prefix, at parse
+// time. Like [Base64], decoding errors don't repeat the value and String returns "".
+//
+// Use [cli.GetFlag] with type []byte to retrieve the decoded bytes.
+func Hex() flag.Value {
+	return &bytesValue{decode: decodeHex}
+}
+
+// String returns "" rather than the encoded value, which may be a secret.
+func (v *bytesValue) String() string {
+	return ""
+}
+
+func (v *bytesValue) Set(s string) error {
+	data, err := v.decode(s)
+	if err != nil {
+		return err
+	}
+	v.data = data
+	return nil
+}
+
+func (v *bytesValue) Get() any {
+	return v.data
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	encodings := []*base64.Encoding{
+		base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding,
+	}
+	var firstErr error
+	for _, enc := range encodings {
+		data, err := enc.DecodeString(s)
+		if err == nil {
+			return data, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, fmt.Errorf("invalid base64 value: %w", firstErr)
+}
+
+func decodeHex(s string) ([]byte, error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	data, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex value: %w", err)
+	}
+	return data, nil
+}
@@ -7,9 +7,34 @@ import (
 	"strings"
 )
 
+// EnumOption configures enum flags created by [EnumWith] or [EnumDefault].
+type EnumOption func(*enumValue)
+
+// WithCaseInsensitive matches values regardless of case, so --format=JSON selects "json". The
+// retrieved value is always the spelling from the allowed list.
+func WithCaseInsensitive() EnumOption {
+	return func(v *enumValue) {
+		v.foldCase = true
+	}
+}
+
+// WithAlias accepts alias as another name for value, like WithAlias("yml", "yaml"). The retrieved
+// value is always value. Aliases are not listed in error messages or by [AllowedValues]. Value
+// must be one of the allowed values, otherwise the enum constructor panics.
+func WithAlias(alias, value string) EnumOption {
+	return func(v *enumValue) {
+		if v.aliases == nil {
+			v.aliases = make(map[string]string)
+		}
+		v.aliases[alias] = value
+	}
+}
+
 type enumValue struct {
-	val     string
-	allowed []string
+	val      string
+	allowed  []string
+	foldCase bool
+	aliases  map[string]string
 }
 
 // Enum returns a [flag.Value] that restricts the flag to one of the allowed values. If a value not
@@ -17,19 +42,46 @@ type enumValue struct {
 //
 // Use [cli.GetFlag] with type string to retrieve the value.
 func Enum(allowed ...string) flag.Value {
-	return &enumValue{allowed: allowed}
+	return EnumWith(allowed)
+}
+
+// EnumWith is like [Enum] but accepts enum options, for example to match case-insensitively or to
+// accept aliases:
+//
+//	flagtype.EnumWith([]string{"json", "yaml", "table"},
+//	    flagtype.WithCaseInsensitive(), flagtype.WithAlias("yml", "yaml"))
+//
+// Use [cli.GetFlag] with type string to retrieve the value.
+func EnumWith(allowed []string, opts ...EnumOption) flag.Value {
+	return newEnumValue(allowed, opts)
 }
 
 // EnumDefault is like [Enum] but sets an initial default value. The default must be one of the
 // allowed values, otherwise EnumDefault panics.
 //
 // Use [cli.GetFlag] with type string to retrieve the value.
-func EnumDefault(defaultVal string, allowed []string) flag.Value {
+func EnumDefault(defaultVal string, allowed []string, opts ...EnumOption) flag.Value {
 	if !slices.Contains(allowed, defaultVal) {
 		panic(fmt.Sprintf("flagtype: default value %q is not in allowed values: %s",
 			defaultVal, strings.Join(allowed, ", ")))
 	}
-	return &enumValue{val: defaultVal, allowed: allowed}
+	v := newEnumValue(allowed, opts)
+	v.val = defaultVal
+	return v
+}
+
+func newEnumValue(allowed []string, opts []EnumOption) *enumValue {
+	v := &enumValue{allowed: allowed}
+	for _, opt := range opts {
+		opt(v)
+	}
+	for alias, value := range v.aliases {
+		if !slices.Contains(allowed, value) {
+			panic(fmt.Sprintf("flagtype: alias %q refers to %q, which is not in allowed values: %s",
+				alias, value, strings.Join(allowed, ", ")))
+		}
+	}
+	return v
 }
 
 func (v *enumValue) String() string {
@@ -37,13 +89,53 @@ func (v *enumValue) String() string {
 }
 
 func (v *enumValue) Set(s string) error {
-	if !slices.Contains(v.allowed, s) {
+	val, ok := v.match(s)
+	if !ok {
 		return fmt.Errorf("invalid value %q, must be one of: %s", s, strings.Join(v.allowed, ", "))
 	}
-	v.val = s
+	v.val = val
 	return nil
 }
 
+// match resolves s to its canonical allowed value, following aliases and case folding.
+func (v *enumValue) match(s string) (string, bool) {
+	equal := func(a, b string) bool { return a == b }
+	if v.foldCase {
+		equal = strings.EqualFold
+	}
+	for _, a := range v.allowed {
+		if equal(a, s) {
+			return a, true
+		}
+	}
+	for alias, value := range v.aliases {
+		if equal(alias, s) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
 func (v *enumValue) Get() any {
 	return v.val
 }
+
+// Allowed returns the values the flag accepts, in the order they were given.
+func (v *enumValue) Allowed() []string {
+	return slices.Clone(v.allowed)
+}
+
+// Describe lists the allowed values for help output.
+func (v *enumValue) Describe() string {
+	return "one of: " + strings.Join(v.allowed, ", ")
+}
+
+// AllowedValues returns the values accepted by an enum flag created by [Enum], [EnumWith],
+// [EnumDefault], or [EnumSlice], so help and completion can list the choices. It returns nil for
+// any other [flag.Value].
+func AllowedValues(v flag.Value) []string {
+	if a, ok := v.(interface{ Allowed() []string }); ok {
+		return a.Allowed()
+	}
+	return nil
+}
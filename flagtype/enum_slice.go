@@ -0,0 +1,53 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type enumSliceValue struct {
+	sliceValue[string]
+	allowed []string
+}
+
+// EnumSlice returns a [flag.Value] that collects values restricted to the allowed set into a
+// slice, like --capability=net --capability=fs. Each value not in the allowed list is rejected
+// with an error listing valid options. Duplicates are kept; use [EnumSliceWith] to change that.
+//
+// Use [cli.GetFlag] with type []string to retrieve the value.
+func EnumSlice(allowed ...string) flag.Value {
+	return EnumSliceWith(allowed)
+}
+
+// EnumSliceWith is like [EnumSlice] but accepts slice options, for example to split
+// comma-separated values or drop duplicates:
+//
+//	flagtype.EnumSliceWith([]string{"net", "fs", "ipc"},
+//	    flagtype.WithSeparator(","), flagtype.WithDuplicates(flagtype.IgnoreDuplicates))
+//
+// Use [cli.GetFlag] with type []string to retrieve the value.
+func EnumSliceWith(allowed []string, opts ...SliceOption) flag.Value {
+	parse := func(s string) (string, error) {
+		if !slices.Contains(allowed, s) {
+			return "", fmt.Errorf("invalid value %q, must be one of: %s", s, strings.Join(allowed, ", "))
+		}
+		return s, nil
+	}
+	return &enumSliceValue{newSliceValue(parse, identity, opts), allowed}
+}
+
+// Allowed returns the values the flag accepts, in the order they were given.
+func (v *enumSliceValue) Allowed() []string {
+	return slices.Clone(v.allowed)
+}
+
+// Describe lists the allowed values for help output.
+func (v *enumSliceValue) Describe() string {
+	return "one of: " + strings.Join(v.allowed, ", ")
+}
+
+func (v *enumSliceValue) Clone() flag.Value {
+	return &enumSliceValue{v.sliceValue.clone(), v.allowed}
+}
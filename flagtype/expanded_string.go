@@ -0,0 +1,79 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ExpandOption configures flags created by [ExpandedString].
+type ExpandOption func(*expandedStringValue)
+
+// WithErrorOnUnset makes [ExpandedString] return an error naming any referenced environment
+// variables that are not set, instead of replacing them with the empty string.
+func WithErrorOnUnset() ExpandOption {
+	return func(v *expandedStringValue) {
+		v.errorOnUnset = true
+	}
+}
+
+type expandedStringValue struct {
+	val          string
+	errorOnUnset bool
+	lookupEnv    func(string) (string, bool)
+}
+
+// ExpandedString returns a [flag.Value] that expands $VAR and ${VAR} references in the value using
+// the environment, like [os.ExpandEnv]. This helps when the shell didn't expand the value, as with
+// --path='$HOME/data', or when the value comes from a config file. Unset variables expand to the
+// empty string unless [WithErrorOnUnset] is given. Variables are read with the function set with
+// SetLookupEnv, if any.
+//
+// Use [cli.GetFlag] with type string to retrieve the expanded value.
+func ExpandedString(opts ...ExpandOption) flag.Value {
+	v := &expandedStringValue{}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+func (v *expandedStringValue) String() string {
+	return v.val
+}
+
+func (v *expandedStringValue) Set(s string) error {
+	var unset []string
+	val := os.Expand(s, func(name string) string {
+		val, ok := lookupEnv(v.lookupEnv, name)
+		if !ok {
+			unset = append(unset, name)
+		}
+		return val
+	})
+	if v.errorOnUnset && len(unset) > 0 {
+		return fmt.Errorf("invalid value %q: environment variable not set: %s", s, strings.Join(unset, ", "))
+	}
+	v.val = val
+	return nil
+}
+
+func (v *expandedStringValue) Get() any {
+	return v.val
+}
+
+// SetLookupEnv sets the function that environment variables are read with, or restores
+// [os.LookupEnv] if lookupEnv is nil. The cli package calls it with the LookupEnv from its
+// RunOptions before parsing.
+func (v *expandedStringValue) SetLookupEnv(lookupEnv func(string) (string, bool)) {
+	v.lookupEnv = lookupEnv
+}
+
+// lookupEnv reads the environment variable key with fn, or with [os.LookupEnv] if fn is nil.
+func lookupEnv(fn func(string) (string, bool), key string) (string, bool) {
+	if fn == nil {
+		return os.LookupEnv(key)
+	}
+	return fn(key)
+}
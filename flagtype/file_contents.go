@@ -0,0 +1,58 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// stdin is the reader used for the "-" path of [FileContents], replaced in tests.
+var stdin io.Reader = os.Stdin
+
+type fileContentsValue struct {
+	path string
+	data []byte
+	base string
+}
+
+// FileContents returns a [flag.Value] that reads the file named by the flag value at parse time,
+// like --ca-cert=ca.pem or --body=request.json. A value of "-" reads from standard input. The path
+// is shown in help and error output, never the contents. Relative paths are read from the
+// directory set with SetBaseDir, if any.
+//
+// Use [cli.GetFlag] with type []byte to retrieve the contents.
+func FileContents() flag.Value {
+	return &fileContentsValue{}
+}
+
+func (v *fileContentsValue) String() string {
+	return v.path
+}
+
+func (v *fileContentsValue) Set(s string) error {
+	var (
+		data []byte
+		err  error
+	)
+	if s == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(resolvePath(v.base, s))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %q: %w", s, err)
+	}
+	v.path, v.data = s, data
+	return nil
+}
+
+func (v *fileContentsValue) Get() any {
+	return v.data
+}
+
+// SetBaseDir sets the directory that relative paths are read from. The cli package calls it with
+// the directory from its RunOptions before parsing.
+func (v *fileContentsValue) SetBaseDir(dir string) {
+	v.base = dir
+}
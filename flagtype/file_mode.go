@@ -0,0 +1,66 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type fileModeValue struct {
+	mode os.FileMode
+	set  bool
+}
+
+// FileMode returns a [flag.Value] that parses Unix permissions written in octal, like 0644, 755, or
+// 0o600. A fourth leading digit sets the setuid (4), setgid (2), and sticky (1) bits, like 4755.
+//
+// Use [cli.GetFlag] with type os.FileMode to retrieve the value.
+func FileMode() flag.Value {
+	return &fileModeValue{}
+}
+
+func (v *fileModeValue) String() string {
+	if !v.set {
+		return ""
+	}
+	n := uint64(v.mode.Perm())
+	if v.mode&os.ModeSetuid != 0 {
+		n |= 0o4000
+	}
+	if v.mode&os.ModeSetgid != 0 {
+		n |= 0o2000
+	}
+	if v.mode&os.ModeSticky != 0 {
+		n |= 0o1000
+	}
+	return fmt.Sprintf("%04o", n)
+}
+
+func (v *fileModeValue) Set(s string) error {
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "0o"), "0O")
+	n, err := strconv.ParseUint(digits, 8, 32)
+	if err != nil || digits == "" {
+		return fmt.Errorf("invalid file mode %q, must be an octal number like 0644", s)
+	}
+	if n > 0o7777 {
+		return fmt.Errorf("invalid file mode %q, must be at most 07777", s)
+	}
+	mode := os.FileMode(n & 0o777)
+	if n&0o4000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if n&0o2000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if n&0o1000 != 0 {
+		mode |= os.ModeSticky
+	}
+	v.mode, v.set = mode, true
+	return nil
+}
+
+func (v *fileModeValue) Get() any {
+	return v.mode
+}
@@ -1,10 +1,27 @@
 package flagtype
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"flag"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/netip"
 	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"text/template"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -77,6 +94,62 @@ func TestEnum(t *testing.T) {
 	})
 }
 
+func TestEnumWith(t *testing.T) {
+	t.Parallel()
+
+	t.Run("case insensitive", func(t *testing.T) {
+		t.Parallel()
+		v := EnumWith([]string{"json", "YAML"}, WithCaseInsensitive())
+		require.NoError(t, v.Set("JSON"))
+		assert.Equal(t, "json", v.(flag.Getter).Get())
+		require.NoError(t, v.Set("yaml"))
+		assert.Equal(t, "YAML", v.(flag.Getter).Get())
+	})
+	t.Run("case sensitive by default", func(t *testing.T) {
+		t.Parallel()
+		v := Enum("json")
+		require.Error(t, v.Set("JSON"))
+	})
+	t.Run("aliases", func(t *testing.T) {
+		t.Parallel()
+		v := EnumWith([]string{"json", "yaml"}, WithAlias("yml", "yaml"))
+		require.NoError(t, v.Set("yml"))
+		assert.Equal(t, "yaml", v.(flag.Getter).Get())
+		err := v.Set("YML")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be one of: json, yaml")
+	})
+	t.Run("aliases with case folding", func(t *testing.T) {
+		t.Parallel()
+		v := EnumWith([]string{"json", "yaml"}, WithAlias("yml", "yaml"), WithCaseInsensitive())
+		require.NoError(t, v.Set("YML"))
+		assert.Equal(t, "yaml", v.String())
+	})
+	t.Run("alias to unknown value panics", func(t *testing.T) {
+		t.Parallel()
+		assert.PanicsWithValue(t,
+			`flagtype: alias "yml" refers to "yaml", which is not in allowed values: json`,
+			func() { EnumWith([]string{"json"}, WithAlias("yml", "yaml")) },
+		)
+	})
+	t.Run("default with options", func(t *testing.T) {
+		t.Parallel()
+		v := EnumDefault("table", []string{"json", "table"}, WithCaseInsensitive())
+		assert.Equal(t, "table", v.String())
+		require.NoError(t, v.Set("Json"))
+		assert.Equal(t, "json", v.String())
+	})
+}
+
+func TestAllowedValues(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"a", "b"}, AllowedValues(Enum("a", "b")))
+	assert.Equal(t, []string{"a", "b"}, AllowedValues(EnumDefault("a", []string{"a", "b"})))
+	assert.Equal(t, []string{"x", "y"}, AllowedValues(EnumSlice("x", "y")))
+	assert.Nil(t, AllowedValues(StringSlice()))
+}
+
 func TestEnumDefault(t *testing.T) {
 	t.Parallel()
 
@@ -202,6 +275,51 @@ func TestURL(t *testing.T) {
 	})
 }
 
+func TestURLScheme(t *testing.T) {
+	t.Parallel()
+
+	t.Run("allowed scheme", func(t *testing.T) {
+		t.Parallel()
+		v := URLScheme("https", "postgres")
+		require.NoError(t, v.Set("postgres://user@db.example.com:5432/app"))
+		require.NoError(t, v.Set("HTTPS://example.com"))
+		got := v.(flag.Getter).Get().(*url.URL)
+		assert.Equal(t, "example.com", got.Host)
+	})
+	t.Run("disallowed scheme", func(t *testing.T) {
+		t.Parallel()
+		v := URLScheme("https", "postgres")
+		err := v.Set("http://example.com")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "scheme must be one of: https, postgres")
+		assert.Nil(t, v.(flag.Getter).Get())
+	})
+}
+
+func TestURLSlice(t *testing.T) {
+	t.Parallel()
+
+	t.Run("multiple values", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(URLSlice(), "endpoint", "")
+		err := fs.Parse([]string{"--endpoint=https://a.example.com", "--endpoint=http://b.example.com:8080/x"})
+		require.NoError(t, err)
+		got := fs.Lookup("endpoint").Value.(flag.Getter).Get().([]*url.URL)
+		require.Len(t, got, 2)
+		assert.Equal(t, "a.example.com", got[0].Host)
+		assert.Equal(t, "b.example.com:8080", got[1].Host)
+		assert.Equal(t, "https://a.example.com,http://b.example.com:8080/x", fs.Lookup("endpoint").Value.String())
+	})
+	t.Run("invalid url", func(t *testing.T) {
+		t.Parallel()
+		v := URLSlice()
+		err := v.Set("example.com")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must have a scheme and host")
+	})
+}
+
 func TestRegexp(t *testing.T) {
 	t.Parallel()
 
@@ -232,6 +350,1613 @@ func TestRegexp(t *testing.T) {
 	})
 }
 
+func TestCount(t *testing.T) {
+	t.Parallel()
+
+	t.Run("counts occurrences", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(Count(), "v", "")
+		err := fs.Parse([]string{"-v", "-v", "-v", "arg"})
+		require.NoError(t, err)
+		got := fs.Lookup("v").Value.(flag.Getter).Get().(int)
+		assert.Equal(t, 3, got)
+		assert.Equal(t, []string{"arg"}, fs.Args())
+	})
+	t.Run("rejects explicit value", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(nopWriter{})
+		fs.Var(Count(), "v", "")
+		err := fs.Parse([]string{"-v=high"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not take a value")
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := Count()
+		assert.Equal(t, "0", v.String())
+		assert.Equal(t, 0, v.(flag.Getter).Get())
+	})
+}
+
+func TestNumberSlices(t *testing.T) {
+	t.Parallel()
+
+	t.Run("int slice", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(IntSlice(), "port", "")
+		err := fs.Parse([]string{"--port=80", "--port", "443"})
+		require.NoError(t, err)
+		got := fs.Lookup("port").Value.(flag.Getter).Get().([]int)
+		assert.Equal(t, []int{80, 443}, got)
+		assert.Equal(t, "80,443", fs.Lookup("port").Value.String())
+	})
+	t.Run("int slice with separator", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(IntSlice(WithSeparator(",")), "port", "")
+		err := fs.Parse([]string{"--port=80, 443", "--port=8080"})
+		require.NoError(t, err)
+		got := fs.Lookup("port").Value.(flag.Getter).Get().([]int)
+		assert.Equal(t, []int{80, 443, 8080}, got)
+	})
+	t.Run("invalid value leaves slice unchanged", func(t *testing.T) {
+		t.Parallel()
+		v := IntSlice(WithSeparator(","))
+		require.NoError(t, v.Set("1"))
+		err := v.Set("2,x")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid integer "x"`)
+		assert.Equal(t, []int{1}, v.(flag.Getter).Get())
+	})
+	t.Run("int64 slice", func(t *testing.T) {
+		t.Parallel()
+		v := Int64Slice()
+		require.NoError(t, v.Set("9223372036854775807"))
+		require.NoError(t, v.Set("-1"))
+		assert.Equal(t, []int64{9223372036854775807, -1}, v.(flag.Getter).Get())
+		assert.Equal(t, "9223372036854775807,-1", v.String())
+		assert.Error(t, v.Set("1.5"))
+	})
+	t.Run("float64 slice", func(t *testing.T) {
+		t.Parallel()
+		v := Float64Slice(WithSeparator(";"))
+		require.NoError(t, v.Set("0.5;1e3"))
+		assert.Equal(t, []float64{0.5, 1000}, v.(flag.Getter).Get())
+		assert.Equal(t, "0.5,1000", v.String())
+		err := v.Set("abc")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid number "abc"`)
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := IntSlice()
+		assert.Equal(t, "", v.String())
+		assert.Nil(t, v.(flag.Getter).Get().([]int))
+	})
+}
+
+func TestDurationSlice(t *testing.T) {
+	t.Parallel()
+
+	t.Run("multiple values", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(DurationSlice(WithSeparator(",")), "retry", "")
+		err := fs.Parse([]string{"--retry=1s,5s", "--retry=1m"})
+		require.NoError(t, err)
+		got := fs.Lookup("retry").Value.(flag.Getter).Get().([]time.Duration)
+		assert.Equal(t, []time.Duration{time.Second, 5 * time.Second, time.Minute}, got)
+		assert.Equal(t, "1s,5s,1m0s", fs.Lookup("retry").Value.String())
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		t.Parallel()
+		err := DurationSlice().Set("soon")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid duration "soon"`)
+	})
+}
+
+func TestDurationRange(t *testing.T) {
+	t.Parallel()
+
+	t.Run("within bounds", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(DurationRange(time.Second, time.Minute), "timeout", "")
+		err := fs.Parse([]string{"--timeout=30s"})
+		require.NoError(t, err)
+		got := fs.Lookup("timeout").Value.(flag.Getter).Get().(time.Duration)
+		assert.Equal(t, 30*time.Second, got)
+	})
+	t.Run("bounds are inclusive", func(t *testing.T) {
+		t.Parallel()
+		v := DurationRange(time.Second, time.Minute)
+		require.NoError(t, v.Set("1s"))
+		require.NoError(t, v.Set("1m"))
+	})
+	t.Run("out of bounds", func(t *testing.T) {
+		t.Parallel()
+		v := DurationRange(time.Second, time.Minute)
+		require.NoError(t, v.Set("10s"))
+		err := v.Set("2m")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be between 1s and 1m0s")
+		assert.Equal(t, 10*time.Second, v.(flag.Getter).Get())
+		assert.Error(t, v.Set("500ms"))
+	})
+	t.Run("invalid duration", func(t *testing.T) {
+		t.Parallel()
+		err := DurationRange(0, time.Hour).Set("forever")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid duration "forever"`)
+	})
+	t.Run("panics on inverted bounds", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() { DurationRange(time.Minute, time.Second) })
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := DurationRange(0, time.Hour)
+		assert.Equal(t, "0s", v.String())
+		assert.Equal(t, time.Duration(0), v.(flag.Getter).Get())
+	})
+}
+
+func TestIntRange(t *testing.T) {
+	t.Parallel()
+
+	t.Run("within bounds", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(IntRange(1, 8), "threads", "")
+		err := fs.Parse([]string{"--threads=8"})
+		require.NoError(t, err)
+		got := fs.Lookup("threads").Value.(flag.Getter).Get().(int)
+		assert.Equal(t, 8, got)
+		assert.Equal(t, "8", fs.Lookup("threads").Value.String())
+	})
+	t.Run("out of bounds", func(t *testing.T) {
+		t.Parallel()
+		v := IntRange(1, 8)
+		err := v.Set("0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be between 1 and 8")
+		require.Error(t, v.Set("9"))
+		require.Error(t, v.Set("many"))
+		assert.Equal(t, 0, v.(flag.Getter).Get())
+	})
+	t.Run("no default until set", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(IntRange(5, 10), "threads", "worker threads")
+		var buf strings.Builder
+		fs.SetOutput(&buf)
+		fs.PrintDefaults()
+		assert.NotContains(t, buf.String(), "default")
+		assert.NotContains(t, buf.String(), "panic")
+		assert.Empty(t, (&intRangeValue{}).String())
+	})
+	t.Run("min greater than max panics", func(t *testing.T) {
+		t.Parallel()
+		assert.PanicsWithValue(t,
+			"flagtype: range minimum 5 is greater than maximum 1",
+			func() { IntRange(5, 1) },
+		)
+	})
+}
+
+func TestFloat64Range(t *testing.T) {
+	t.Parallel()
+
+	v := Float64Range(0, 1)
+	require.NoError(t, v.Set("0.25"))
+	assert.Equal(t, 0.25, v.(flag.Getter).Get())
+	assert.Equal(t, "0.25", v.String())
+	err := v.Set("1.5")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be between 0 and 1")
+	assert.Equal(t, 0.25, v.(flag.Getter).Get())
+}
+
+func TestByteSize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid sizes", func(t *testing.T) {
+		t.Parallel()
+		tests := map[string]int64{
+			"0":       0,
+			"512":     512,
+			"512B":    512,
+			"64KB":    64000,
+			"64kb":    64000,
+			"10MiB":   10 << 20,
+			"1.5GB":   1500000000,
+			"1.5 GiB": 3 << 29,
+			" 2 TiB ": 2 << 40,
+			"1PB":     1e15,
+			"7EiB":    7 << 60,
+			"0.5KiB":  512,
+		}
+		for input, want := range tests {
+			v := ByteSize()
+			require.NoError(t, v.Set(input), input)
+			assert.Equal(t, want, v.(flag.Getter).Get(), input)
+		}
+	})
+	t.Run("invalid sizes", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{"", "KB", "-1KB", "10XB", "1.2.3MB", "8EiB", "9999999999999999999"} {
+			err := ByteSize().Set(input)
+			assert.Error(t, err, input)
+		}
+		err := ByteSize().Set("10XB")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown unit "XB"`)
+	})
+	t.Run("flag set", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(ByteSize(), "max-size", "")
+		err := fs.Parse([]string{"--max-size=10MiB"})
+		require.NoError(t, err)
+		got := fs.Lookup("max-size").Value.(flag.Getter).Get().(int64)
+		assert.Equal(t, int64(10<<20), got)
+	})
+	t.Run("string output round-trips", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{"10MiB", "64KB", "1500B", "1537B", "3GB"} {
+			v := ByteSize()
+			require.NoError(t, v.Set(input))
+			assert.Equal(t, input, v.String())
+		}
+		v := ByteSize()
+		require.NoError(t, v.Set("1024"))
+		assert.Equal(t, "1KiB", v.String())
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := ByteSize()
+		assert.Equal(t, "0", v.String())
+		assert.Equal(t, int64(0), v.(flag.Getter).Get())
+	})
+}
+
+func TestTime(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rfc3339 by default", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(Time(), "since", "")
+		err := fs.Parse([]string{"--since=2026-01-02T15:04:05Z"})
+		require.NoError(t, err)
+		got := fs.Lookup("since").Value.(flag.Getter).Get().(time.Time)
+		assert.Equal(t, time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC), got)
+		assert.Equal(t, "2026-01-02T15:04:05Z", fs.Lookup("since").Value.String())
+	})
+	t.Run("custom layouts", func(t *testing.T) {
+		t.Parallel()
+		v := Time(time.DateOnly, time.DateTime)
+		require.NoError(t, v.Set("2026-03-04"))
+		assert.Equal(t, time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), v.(flag.Getter).Get())
+		require.NoError(t, v.Set("2026-03-04 10:30:00"))
+		assert.Equal(t, time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC), v.(flag.Getter).Get())
+		assert.Equal(t, "2026-03-04", v.String())
+	})
+	t.Run("invalid time", func(t *testing.T) {
+		t.Parallel()
+		err := Time(time.DateOnly).Set("yesterday")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid time "yesterday", must match layout: 2006-01-02`)
+	})
+	t.Run("relative", func(t *testing.T) {
+		start := time.Now()
+		v := Time()
+		require.NoError(t, v.Set("now"))
+		got := v.(flag.Getter).Get().(time.Time)
+		assert.False(t, got.Before(start))
+		require.NoError(t, v.Set("now-24h"))
+		got = v.(flag.Getter).Get().(time.Time)
+		assert.WithinDuration(t, start.Add(-24*time.Hour), got, time.Minute)
+		require.NoError(t, v.Set("now+1h30m"))
+		got = v.(flag.Getter).Get().(time.Time)
+		assert.WithinDuration(t, start.Add(90*time.Minute), got, time.Minute)
+	})
+	t.Run("invalid relative", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{"now24h", "now-", "now-day"} {
+			assert.Error(t, Time().Set(input), input)
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := Time()
+		assert.Equal(t, "", v.String())
+		assert.True(t, v.(flag.Getter).Get().(time.Time).IsZero())
+	})
+}
+
+func TestIP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ipv4 and ipv6", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(IP(), "bind", "")
+		err := fs.Parse([]string{"--bind=192.168.1.10"})
+		require.NoError(t, err)
+		got := fs.Lookup("bind").Value.(flag.Getter).Get().(netip.Addr)
+		assert.Equal(t, netip.MustParseAddr("192.168.1.10"), got)
+
+		v := IP()
+		require.NoError(t, v.Set("::1"))
+		assert.True(t, v.(flag.Getter).Get().(netip.Addr).IsLoopback())
+		assert.Equal(t, "::1", v.String())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{"", "localhost", "256.0.0.1", "10.0.0.0/8"} {
+			err := IP().Set(input)
+			require.Error(t, err, input)
+			assert.Contains(t, err.Error(), "invalid IP address")
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := IP()
+		assert.Equal(t, "", v.String())
+		assert.False(t, v.(flag.Getter).Get().(netip.Addr).IsValid())
+	})
+}
+
+func TestCIDR(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(CIDR(), "allow", "")
+		err := fs.Parse([]string{"--allow=10.1.2.3/8"})
+		require.NoError(t, err)
+		got := fs.Lookup("allow").Value.(flag.Getter).Get().(netip.Prefix)
+		assert.Equal(t, 8, got.Bits())
+		assert.Equal(t, "10.0.0.0/8", got.Masked().String())
+		assert.True(t, got.Contains(netip.MustParseAddr("10.200.0.1")))
+		assert.Equal(t, "10.1.2.3/8", fs.Lookup("allow").Value.String())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{"10.0.0.0", "10.0.0.0/33", "2001:db8::/129", "net"} {
+			err := CIDR().Set(input)
+			require.Error(t, err, input)
+			assert.Contains(t, err.Error(), "invalid CIDR")
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := CIDR()
+		assert.Equal(t, "", v.String())
+		assert.False(t, v.(flag.Getter).Get().(netip.Prefix).IsValid())
+	})
+}
+
+func TestHostPort(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		tests := map[string]HostAndPort{
+			"localhost:8080":    {Host: "localhost", Port: 8080},
+			":443":              {Host: "", Port: 443},
+			"10.0.0.1:65535":    {Host: "10.0.0.1", Port: 65535},
+			"[::1]:8080":        {Host: "::1", Port: 8080},
+			"[2001:db8::1]:1":   {Host: "2001:db8::1", Port: 1},
+			"example.com:53000": {Host: "example.com", Port: 53000},
+		}
+		for input, want := range tests {
+			v := HostPort()
+			require.NoError(t, v.Set(input), input)
+			assert.Equal(t, want, v.(flag.Getter).Get(), input)
+			assert.Equal(t, input, v.String(), input)
+		}
+	})
+	t.Run("flag set", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(HostPort(), "addr", "")
+		err := fs.Parse([]string{"--addr", "[::1]:9000"})
+		require.NoError(t, err)
+		got := fs.Lookup("addr").Value.(flag.Getter).Get().(HostAndPort)
+		assert.Equal(t, "::1", got.Host)
+		assert.Equal(t, 9000, got.Port)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{"localhost", "::1:8080", "host:0", "host:65536", "host:http", "host:"} {
+			err := HostPort().Set(input)
+			require.Error(t, err, input)
+			assert.Contains(t, err.Error(), "invalid host:port")
+		}
+		err := HostPort().Set("host:99999")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "between 1 and 65535")
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := HostPort()
+		assert.Equal(t, "", v.String())
+		assert.Equal(t, HostAndPort{}, v.(flag.Getter).Get())
+	})
+}
+
+func TestPort(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(Port(), "port", "")
+		err := fs.Parse([]string{"--port=8080"})
+		require.NoError(t, err)
+		assert.Equal(t, 8080, fs.Lookup("port").Value.(flag.Getter).Get())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{"0", "65536", "-1", "http", ""} {
+			err := Port().Set(input)
+			require.Error(t, err, input)
+			assert.Contains(t, err.Error(), "port must be a number between 1 and 65535")
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := Port()
+		assert.Equal(t, "0", v.String())
+		assert.Equal(t, 0, v.(flag.Getter).Get())
+	})
+}
+
+func TestPath(t *testing.T) {
+	t.Parallel()
+
+	t.Run("absolute and clean", func(t *testing.T) {
+		t.Parallel()
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(Path(), "out", "")
+		err = fs.Parse([]string{"--out=./data/../out.txt"})
+		require.NoError(t, err)
+		got := fs.Lookup("out").Value.(flag.Getter).Get().(string)
+		assert.Equal(t, filepath.Join(wd, "out.txt"), got)
+	})
+	t.Run("already absolute", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		v := Path()
+		require.NoError(t, v.Set(dir+"/a//b/"))
+		assert.Equal(t, filepath.Join(dir, "a", "b"), v.String())
+	})
+	t.Run("empty path", func(t *testing.T) {
+		t.Parallel()
+		assert.Error(t, Path().Set(""))
+	})
+	t.Run("base directory", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		v := Path()
+		v.(interface{ SetBaseDir(string) }).SetBaseDir(dir)
+		require.NoError(t, v.Set("data/out.txt"))
+		assert.Equal(t, filepath.Join(dir, "data", "out.txt"), v.String())
+		require.NoError(t, v.Set("/tmp/out.txt"))
+		assert.Equal(t, "/tmp/out.txt", v.String())
+	})
+}
+
+func TestExistingFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "tasks.json")
+	require.NoError(t, os.WriteFile(file, []byte("{}"), 0o644))
+
+	t.Run("existing file", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(ExistingFile(), "file", "")
+		err := fs.Parse([]string{"--file", file})
+		require.NoError(t, err)
+		assert.Equal(t, file, fs.Lookup("file").Value.(flag.Getter).Get())
+	})
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+		err := ExistingFile().Set(filepath.Join(dir, "missing.json"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing.json\" does not exist")
+	})
+	t.Run("directory", func(t *testing.T) {
+		t.Parallel()
+		err := ExistingFile().Set(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is a directory, expected a file")
+	})
+	t.Run("base directory", func(t *testing.T) {
+		t.Parallel()
+		v := ExistingFile()
+		v.(interface{ SetBaseDir(string) }).SetBaseDir(dir)
+		require.NoError(t, v.Set("tasks.json"))
+		assert.Equal(t, file, v.String())
+	})
+}
+
+func TestExistingDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	require.NoError(t, os.WriteFile(file, nil, 0o644))
+
+	t.Run("existing directory", func(t *testing.T) {
+		t.Parallel()
+		v := ExistingDir()
+		require.NoError(t, v.Set(dir))
+		assert.Equal(t, dir, v.(flag.Getter).Get())
+	})
+	t.Run("missing directory", func(t *testing.T) {
+		t.Parallel()
+		err := ExistingDir().Set(filepath.Join(dir, "nope"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not exist")
+	})
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+		err := ExistingDir().Set(file)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not a directory")
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := ExistingDir()
+		assert.Equal(t, "", v.String())
+		assert.Equal(t, "", v.(flag.Getter).Get())
+	})
+}
+
+func TestFileContents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads file", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(t.TempDir(), "ca.pem")
+		require.NoError(t, os.WriteFile(file, []byte("certificate"), 0o644))
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(FileContents(), "ca-cert", "")
+		err := fs.Parse([]string{"--ca-cert", file})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("certificate"), fs.Lookup("ca-cert").Value.(flag.Getter).Get())
+		assert.Equal(t, file, fs.Lookup("ca-cert").Value.String())
+	})
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+		err := FileContents().Set(filepath.Join(t.TempDir(), "missing"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := FileContents()
+		assert.Equal(t, "", v.String())
+		assert.Nil(t, v.(flag.Getter).Get().([]byte))
+	})
+}
+
+// TestFileContentsStdin is not parallel because it replaces the package-level stdin reader.
+func TestFileContentsStdin(t *testing.T) {
+	original := stdin
+	t.Cleanup(func() { stdin = original })
+	stdin = strings.NewReader(`{"name":"x"}`)
+
+	v := FileContents()
+	require.NoError(t, v.Set("-"))
+	assert.Equal(t, []byte(`{"name":"x"}`), v.(flag.Getter).Get())
+	assert.Equal(t, "-", v.String())
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("object", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(JSON(), "labels", "")
+		err := fs.Parse([]string{`--labels={"env":"prod","replicas":3,"tags":["a"]}`})
+		require.NoError(t, err)
+		got := fs.Lookup("labels").Value.(flag.Getter).Get().(map[string]any)
+		assert.Equal(t, map[string]any{"env": "prod", "replicas": float64(3), "tags": []any{"a"}}, got)
+		assert.Equal(t, `{"env":"prod","replicas":3,"tags":["a"]}`, fs.Lookup("labels").Value.String())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{`{"env":`, `not json`, `[1,2]`, `"str"`} {
+			err := JSON().Set(input)
+			require.Error(t, err, input)
+			assert.Contains(t, err.Error(), "invalid JSON")
+		}
+	})
+	t.Run("replaces previous value", func(t *testing.T) {
+		t.Parallel()
+		v := JSON()
+		require.NoError(t, v.Set(`{"a":1}`))
+		require.NoError(t, v.Set(`{"b":2}`))
+		assert.Equal(t, map[string]any{"b": float64(2)}, v.(flag.Getter).Get())
+	})
+	t.Run("into struct", func(t *testing.T) {
+		t.Parallel()
+		type config struct {
+			Name    string   `json:"name"`
+			Retries int      `json:"retries"`
+			Hosts   []string `json:"hosts"`
+		}
+		v := JSONInto[config]()
+		require.NoError(t, v.Set(`{"name":"api","retries":2,"hosts":["a","b"]}`))
+		assert.Equal(t, config{Name: "api", Retries: 2, Hosts: []string{"a", "b"}}, v.(flag.Getter).Get())
+
+		err := v.Set(`{"retries":"two"}`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid JSON")
+		assert.Equal(t, "api", v.(flag.Getter).Get().(config).Name)
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := JSON()
+		assert.Equal(t, "", v.String())
+		assert.Nil(t, v.(flag.Getter).Get().(map[string]any))
+	})
+}
+
+func TestUUID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		const want = "123e4567-e89b-12d3-a456-426614174000"
+		for _, input := range []string{
+			"123e4567-e89b-12d3-a456-426614174000",
+			"123E4567-E89B-12D3-A456-426614174000",
+			"123e4567e89b12d3a456426614174000",
+		} {
+			v := UUID()
+			require.NoError(t, v.Set(input), input)
+			assert.Equal(t, want, v.(flag.Getter).Get(), input)
+			assert.Equal(t, want, v.String(), input)
+		}
+	})
+	t.Run("flag set", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(UUID(), "id", "")
+		err := fs.Parse([]string{"--id=00000000000000000000000000000000"})
+		require.NoError(t, err)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000000", fs.Lookup("id").Value.(flag.Getter).Get())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{
+			"",
+			"123",
+			"123e4567-e89b-12d3-a456-42661417400g",
+			"123e4567_e89b_12d3_a456_426614174000",
+			"123e4567-e89b12d3-a456-4266141740000",
+			"{123e4567-e89b-12d3-a456-426614174000}",
+		} {
+			err := UUID().Set(input)
+			require.Error(t, err, input)
+			assert.Contains(t, err.Error(), "invalid UUID")
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := UUID()
+		assert.Equal(t, "", v.String())
+		assert.Equal(t, "", v.(flag.Getter).Get())
+	})
+}
+
+func TestSemver(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		tests := map[string]Version{
+			"1.2.3":                  {Major: 1, Minor: 2, Patch: 3},
+			"v0.10.0":                {Minor: 10},
+			"2.0.0-rc.1":             {Major: 2, Prerelease: "rc.1"},
+			"1.0.0+build.5":          {Major: 1, Build: "build.5"},
+			"1.0.0-alpha-1.x+sha.ab": {Major: 1, Prerelease: "alpha-1.x", Build: "sha.ab"},
+		}
+		for input, want := range tests {
+			v := Semver()
+			require.NoError(t, v.Set(input), input)
+			assert.Equal(t, want, v.(flag.Getter).Get(), input)
+			assert.Equal(t, strings.TrimPrefix(input, "v"), v.String(), input)
+		}
+	})
+	t.Run("flag set", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(Semver(), "version", "")
+		err := fs.Parse([]string{"--version=v1.4.0"})
+		require.NoError(t, err)
+		got := fs.Lookup("version").Value.(flag.Getter).Get().(Version)
+		assert.Equal(t, "1.4.0", got.String())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{
+			"", "1", "1.2", "1.2.3.4", "01.2.3", "1.02.3", "1.2.x", "V1.2.3",
+			"1.2.3-", "1.2.3-rc..1", "1.2.3-01", "1.2.3+", "1.2.3-rc_1", "-1.2.3",
+		} {
+			err := Semver().Set(input)
+			require.Error(t, err, input)
+			assert.Contains(t, err.Error(), "invalid semantic version", input)
+		}
+	})
+	t.Run("precedence", func(t *testing.T) {
+		t.Parallel()
+		// Ordered lowest to highest, including the example from the SemVer specification.
+		ordered := []string{
+			"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+			"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0",
+		}
+		parse := func(s string) Version {
+			v := Semver()
+			require.NoError(t, v.Set(s))
+			return v.(flag.Getter).Get().(Version)
+		}
+		for i := 0; i < len(ordered)-1; i++ {
+			a, b := parse(ordered[i]), parse(ordered[i+1])
+			assert.True(t, a.LessThan(b), "%s < %s", a, b)
+			assert.Equal(t, -1, a.Compare(b), "%s < %s", a, b)
+			assert.Equal(t, 1, b.Compare(a), "%s > %s", b, a)
+		}
+		assert.Equal(t, 0, parse("1.0.0+a").Compare(parse("1.0.0+b")))
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := Semver()
+		assert.Equal(t, "", v.String())
+		assert.Equal(t, Version{}, v.(flag.Getter).Get())
+	})
+}
+
+func TestEnumSlice(t *testing.T) {
+	t.Parallel()
+
+	t.Run("multiple values", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(EnumSlice("net", "fs", "ipc"), "capability", "")
+		err := fs.Parse([]string{"--capability=net", "--capability=fs", "--capability=net"})
+		require.NoError(t, err)
+		got := fs.Lookup("capability").Value.(flag.Getter).Get().([]string)
+		assert.Equal(t, []string{"net", "fs", "net"}, got)
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(nopWriter{})
+		fs.Var(EnumSlice("net", "fs"), "capability", "")
+		err := fs.Parse([]string{"--capability=net", "--capability=gpu"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid value "gpu", must be one of: net, fs`)
+	})
+	t.Run("ignore duplicates", func(t *testing.T) {
+		t.Parallel()
+		v := EnumSliceWith([]string{"net", "fs", "ipc"}, WithSeparator(","), WithDuplicates(IgnoreDuplicates))
+		require.NoError(t, v.Set("net,fs,net"))
+		require.NoError(t, v.Set("fs"))
+		require.NoError(t, v.Set("ipc"))
+		assert.Equal(t, []string{"net", "fs", "ipc"}, v.(flag.Getter).Get())
+	})
+	t.Run("reject duplicates", func(t *testing.T) {
+		t.Parallel()
+		v := EnumSliceWith([]string{"net", "fs"}, WithSeparator(","), WithDuplicates(RejectDuplicates))
+		require.NoError(t, v.Set("net"))
+		err := v.Set("fs,net")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `duplicate value "net"`)
+		assert.Equal(t, []string{"net"}, v.(flag.Getter).Get())
+		assert.Error(t, EnumSliceWith([]string{"a"}, WithSeparator(","), WithDuplicates(RejectDuplicates)).Set("a,a"))
+	})
+	t.Run("duplicate policy on other slices", func(t *testing.T) {
+		t.Parallel()
+		v := IntSlice(WithDuplicates(IgnoreDuplicates))
+		require.NoError(t, v.Set("1"))
+		require.NoError(t, v.Set("01"))
+		require.NoError(t, v.Set("2"))
+		assert.Equal(t, []int{1, 2}, v.(flag.Getter).Get())
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := EnumSlice("a", "b")
+		assert.Equal(t, "", v.String())
+		assert.Nil(t, v.(flag.Getter).Get().([]string))
+	})
+}
+
+func TestLogLevel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("names", func(t *testing.T) {
+		t.Parallel()
+		tests := map[string]slog.Level{
+			"debug":   slog.LevelDebug,
+			"INFO":    slog.LevelInfo,
+			"Warn":    slog.LevelWarn,
+			"warning": slog.LevelWarn,
+			"error":   slog.LevelError,
+			"warn+2":  slog.LevelWarn + 2,
+			"-4":      slog.LevelDebug,
+			"12":      slog.Level(12),
+		}
+		for input, want := range tests {
+			v := LogLevel()
+			require.NoError(t, v.Set(input), input)
+			assert.Equal(t, want, v.(flag.Getter).Get(), input)
+		}
+	})
+	t.Run("flag set", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(LogLevel(), "log-level", "")
+		err := fs.Parse([]string{"--log-level=debug"})
+		require.NoError(t, err)
+		got := fs.Lookup("log-level").Value.(flag.Getter).Get().(slog.Level)
+		assert.Equal(t, slog.LevelDebug, got)
+		assert.Equal(t, "debug", fs.Lookup("log-level").Value.String())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{"", "verbose", "info+", "1.5"} {
+			err := LogLevel().Set(input)
+			require.Error(t, err, input)
+			assert.Contains(t, err.Error(), "must be one of: debug, info, warn, error")
+		}
+	})
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+		v := LogLevel()
+		assert.Equal(t, "info", v.String())
+		assert.Equal(t, slog.LevelInfo, v.(flag.Getter).Get())
+	})
+}
+
+func TestTimezone(t *testing.T) {
+	t.Parallel()
+
+	t.Run("utc", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(Timezone(), "tz", "")
+		err := fs.Parse([]string{"--tz=UTC"})
+		require.NoError(t, err)
+		got := fs.Lookup("tz").Value.(flag.Getter).Get().(*time.Location)
+		assert.Equal(t, time.UTC, got)
+		assert.Equal(t, "UTC", fs.Lookup("tz").Value.String())
+	})
+	t.Run("named zone", func(t *testing.T) {
+		t.Parallel()
+		if _, err := time.LoadLocation("America/New_York"); err != nil {
+			t.Skip("time zone database not available")
+		}
+		v := Timezone()
+		require.NoError(t, v.Set("America/New_York"))
+		loc := v.(flag.Getter).Get().(*time.Location)
+		assert.Equal(t, "America/New_York", loc.String())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		err := Timezone().Set("Mars/Olympus_Mons")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid time zone "Mars/Olympus_Mons"`)
+		assert.Error(t, Timezone().Set(""))
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := Timezone()
+		assert.Equal(t, "", v.String())
+		assert.Nil(t, v.(flag.Getter).Get().(*time.Location))
+	})
+}
+
+func TestFileMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		tests := map[string]os.FileMode{
+			"0644":  0o644,
+			"644":   0o644,
+			"0o600": 0o600,
+			"0":     0,
+			"4755":  os.ModeSetuid | 0o755,
+			"2775":  os.ModeSetgid | 0o775,
+			"1777":  os.ModeSticky | 0o777,
+		}
+		for input, want := range tests {
+			v := FileMode()
+			require.NoError(t, v.Set(input), input)
+			assert.Equal(t, want, v.(flag.Getter).Get(), input)
+		}
+	})
+	t.Run("flag set", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(FileMode(), "mode", "")
+		err := fs.Parse([]string{"--mode=0640"})
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o640), fs.Lookup("mode").Value.(flag.Getter).Get())
+		assert.Equal(t, "0640", fs.Lookup("mode").Value.String())
+	})
+	t.Run("string output", func(t *testing.T) {
+		t.Parallel()
+		v := FileMode()
+		require.NoError(t, v.Set("4755"))
+		assert.Equal(t, "4755", v.String())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{"", "0o", "rw-r--r--", "0999", "-644", "10000"} {
+			err := FileMode().Set(input)
+			require.Error(t, err, input)
+			assert.Contains(t, err.Error(), "invalid file mode", input)
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := FileMode()
+		assert.Equal(t, "", v.String())
+		assert.Equal(t, os.FileMode(0), v.(flag.Getter).Get())
+	})
+}
+
+func TestKeyPair(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := writeKeyPair(t, dir, "server")
+	otherCert, _ := writeKeyPair(t, dir, "other")
+
+	t.Run("valid pair", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(KeyPair(), "tls", "")
+		err := fs.Parse([]string{"--tls", certFile + "," + keyFile})
+		require.NoError(t, err)
+		got := fs.Lookup("tls").Value.(flag.Getter).Get().(*tls.Certificate)
+		require.NotNil(t, got)
+		assert.Len(t, got.Certificate, 1)
+		assert.Equal(t, certFile+","+keyFile, fs.Lookup("tls").Value.String())
+	})
+	t.Run("mismatched key", func(t *testing.T) {
+		t.Parallel()
+		err := KeyPair().Set(otherCert + "," + keyFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid key pair")
+	})
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+		err := KeyPair().Set(filepath.Join(dir, "missing.crt") + "," + keyFile)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+	t.Run("relative to base dir", func(t *testing.T) {
+		t.Parallel()
+		v := KeyPair()
+		v.(interface{ SetBaseDir(string) }).SetBaseDir(filepath.Dir(certFile))
+		require.NoError(t, v.Set(filepath.Base(certFile)+","+filepath.Base(keyFile)))
+		assert.NotNil(t, v.(flag.Getter).Get().(*tls.Certificate))
+	})
+	t.Run("malformed value", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{"", certFile, certFile + ",", "," + keyFile} {
+			err := KeyPair().Set(input)
+			require.Error(t, err, input)
+			assert.Contains(t, err.Error(), "must be <cert-file>,<key-file>")
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := KeyPair()
+		assert.Equal(t, "", v.String())
+		assert.Nil(t, v.(flag.Getter).Get().(*tls.Certificate))
+	})
+}
+
+// writeKeyPair writes a self-signed certificate and its private key as PEM files in dir.
+func writeKeyPair(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestBase64(t *testing.T) {
+	t.Parallel()
+
+	t.Run("encodings", func(t *testing.T) {
+		t.Parallel()
+		want := []byte{0xfb, 0xff, 0x01}
+		for _, input := range []string{"+/8B", "-_8B"} {
+			v := Base64()
+			require.NoError(t, v.Set(input), input)
+			assert.Equal(t, want, v.(flag.Getter).Get(), input)
+		}
+		for _, input := range []string{"aGk=", "aGk"} {
+			v := Base64()
+			require.NoError(t, v.Set(input), input)
+			assert.Equal(t, []byte("hi"), v.(flag.Getter).Get(), input)
+		}
+	})
+	t.Run("flag set", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(Base64(), "token", "")
+		err := fs.Parse([]string{"--token=c2VjcmV0"})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("secret"), fs.Lookup("token").Value.(flag.Getter).Get())
+		assert.Equal(t, "", fs.Lookup("token").Value.String())
+	})
+	t.Run("default hidden", func(t *testing.T) {
+		t.Parallel()
+		v := Base64()
+		require.NoError(t, v.Set("c2VjcmV0"))
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(v, "token", "")
+		assert.Equal(t, "", fs.Lookup("token").DefValue)
+		assert.Equal(t, []byte("secret"), v.(flag.Getter).Get())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		err := Base64().Set("not*base64")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid base64 value: illegal base64 data at input byte 3")
+		assert.NotContains(t, err.Error(), "not*base64")
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := Base64()
+		assert.Equal(t, "", v.String())
+		assert.Nil(t, v.(flag.Getter).Get().([]byte))
+	})
+}
+
+func TestHex(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{"deadbeef", "DEADBEEF", "0xdeadbeef"} {
+			v := Hex()
+			require.NoError(t, v.Set(input), input)
+			assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, v.(flag.Getter).Get(), input)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		err := Hex().Set("abc")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid hex value: encoding/hex: odd length hex string")
+		err = Hex().Set("zz")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid hex value")
+		assert.NotContains(t, err.Error(), `"zz"`)
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := Hex()
+		assert.Equal(t, "", v.String())
+		assert.Nil(t, v.(flag.Getter).Get().([]byte))
+	})
+}
+
+func TestTemplate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(Template(), "format", "")
+		err := fs.Parse([]string{"--format={{.Name}}: {{.Count}}"})
+		require.NoError(t, err)
+		tmpl := fs.Lookup("format").Value.(flag.Getter).Get().(*template.Template)
+		var b strings.Builder
+		require.NoError(t, tmpl.Execute(&b, map[string]any{"Name": "tasks", "Count": 3}))
+		assert.Equal(t, "tasks: 3", b.String())
+		assert.Equal(t, "{{.Name}}: {{.Count}}", fs.Lookup("format").Value.String())
+	})
+	t.Run("syntax error", func(t *testing.T) {
+		t.Parallel()
+		err := Template().Set("{{.Name")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid template "{{.Name"`)
+	})
+	t.Run("unknown function", func(t *testing.T) {
+		t.Parallel()
+		err := Template().Set("{{upper .Name}}")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `function "upper" not defined`)
+	})
+	t.Run("with funcs", func(t *testing.T) {
+		t.Parallel()
+		v := TemplateWithFuncs(template.FuncMap{"upper": strings.ToUpper})
+		require.NoError(t, v.Set("{{upper .}}"))
+		var b strings.Builder
+		require.NoError(t, v.(flag.Getter).Get().(*template.Template).Execute(&b, "hi"))
+		assert.Equal(t, "HI", b.String())
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := Template()
+		assert.Equal(t, "", v.String())
+		assert.Nil(t, v.(flag.Getter).Get().(*template.Template))
+	})
+}
+
+func TestGlob(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid pattern", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(Glob(), "include", "")
+		err := fs.Parse([]string{"--include=src/**/*_test.go"})
+		require.NoError(t, err)
+		assert.Equal(t, "src/**/*_test.go", fs.Lookup("include").Value.(flag.Getter).Get())
+	})
+	t.Run("invalid pattern", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{"", "[a-", "src/[/x", `a\`} {
+			err := Glob().Set(input)
+			require.Error(t, err, input)
+			assert.Contains(t, err.Error(), "invalid glob pattern")
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := Glob()
+		assert.Equal(t, "", v.String())
+		assert.Equal(t, "", v.(flag.Getter).Get())
+	})
+}
+
+func TestMatchGlob(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/app/main.go", true},
+		{"src/**/*_test.go", "src/a/b/x_test.go", true},
+		{"src/**/*_test.go", "src/x_test.go", true},
+		{"src/**/*_test.go", "lib/x_test.go", false},
+		{"src/**", "src/a/b", true},
+		{"src/**", "src", true},
+		{"a/?/c", "a/b/c", true},
+		{"a/[bc]/d", "a/x/d", false},
+		{"[", "[", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchGlob(tt.pattern, tt.name), "%s ~ %s", tt.pattern, tt.name)
+	}
+}
+
+func TestGlobFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.go", "b.txt", "sub/c.go", "sub/deep/d.go", "sub/deep/e.txt"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, nil, 0o644))
+	}
+	root := filepath.ToSlash(dir)
+
+	t.Run("single level", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(GlobFiles(), "files", "")
+		err := fs.Parse([]string{"--files=" + root + "/*.go"})
+		require.NoError(t, err)
+		got := fs.Lookup("files").Value.(flag.Getter).Get().([]string)
+		assert.Equal(t, []string{filepath.Join(dir, "a.go")}, got)
+		assert.Equal(t, root+"/*.go", fs.Lookup("files").Value.String())
+	})
+	t.Run("recursive", func(t *testing.T) {
+		t.Parallel()
+		v := GlobFiles()
+		require.NoError(t, v.Set(root+"/**/*.go"))
+		assert.Equal(t, []string{
+			filepath.Join(dir, "a.go"),
+			filepath.Join(dir, "sub", "c.go"),
+			filepath.Join(dir, "sub", "deep", "d.go"),
+		}, v.(flag.Getter).Get())
+	})
+	t.Run("no matches", func(t *testing.T) {
+		t.Parallel()
+		v := GlobFiles()
+		require.NoError(t, v.Set(root+"/**/*.rs"))
+		assert.Empty(t, v.(flag.Getter).Get())
+		require.NoError(t, v.Set(root+"/missing/**/*.go"))
+		assert.Empty(t, v.(flag.Getter).Get())
+	})
+}
+
+func TestStringSliceDelim(t *testing.T) {
+	t.Parallel()
+
+	t.Run("splits and repeats", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(StringSliceDelim(","), "tags", "")
+		err := fs.Parse([]string{"--tags=a, b,,c", "--tags=d"})
+		require.NoError(t, err)
+		got := fs.Lookup("tags").Value.(flag.Getter).Get().([]string)
+		assert.Equal(t, []string{"a", "b", "c", "d"}, got)
+		assert.Equal(t, "a,b,c,d", fs.Lookup("tags").Value.String())
+	})
+	t.Run("option on StringSlice", func(t *testing.T) {
+		t.Parallel()
+		v := StringSlice(WithSeparator(";"), WithDuplicates(IgnoreDuplicates))
+		require.NoError(t, v.Set("x;y"))
+		require.NoError(t, v.Set("y;z"))
+		assert.Equal(t, []string{"x", "y", "z"}, v.(flag.Getter).Get())
+	})
+	t.Run("without separator values are kept as-is", func(t *testing.T) {
+		t.Parallel()
+		v := StringSlice()
+		require.NoError(t, v.Set(" a,b "))
+		require.NoError(t, v.Set(""))
+		assert.Equal(t, []string{" a,b ", ""}, v.(flag.Getter).Get())
+	})
+	t.Run("empty occurrence", func(t *testing.T) {
+		t.Parallel()
+		v := StringSliceDelim(",")
+		require.NoError(t, v.Set(""))
+		assert.Empty(t, v.(flag.Getter).Get())
+	})
+}
+
+func TestKeyValueSlice(t *testing.T) {
+	t.Parallel()
+
+	t.Run("preserves order and duplicates", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(KeyValueSlice(), "header", "")
+		err := fs.Parse([]string{"--header=X-B=2", "--header=X-A=1", "--header=X-B=3=4"})
+		require.NoError(t, err)
+		got := fs.Lookup("header").Value.(flag.Getter).Get().([]KV)
+		assert.Equal(t, []KV{{"X-B", "2"}, {"X-A", "1"}, {"X-B", "3=4"}}, got)
+		assert.Equal(t, "X-B=2,X-A=1,X-B=3=4", fs.Lookup("header").Value.String())
+	})
+	t.Run("empty value", func(t *testing.T) {
+		t.Parallel()
+		v := KeyValueSlice()
+		require.NoError(t, v.Set("key="))
+		assert.Equal(t, []KV{{Key: "key"}}, v.(flag.Getter).Get())
+	})
+	t.Run("invalid pairs", func(t *testing.T) {
+		t.Parallel()
+		v := KeyValueSlice()
+		err := v.Set("nope")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing '='")
+		err = v.Set("=value")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "empty key")
+		assert.Nil(t, v.(flag.Getter).Get())
+	})
+}
+
+func TestStringMultiMap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("collects repeated keys", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(StringMultiMap(), "param", "")
+		err := fs.Parse([]string{"--param=id=2", "--param=q=a=b", "--param=id=1"})
+		require.NoError(t, err)
+		got := fs.Lookup("param").Value.(flag.Getter).Get().(map[string][]string)
+		assert.Equal(t, map[string][]string{"id": {"2", "1"}, "q": {"a=b"}}, got)
+		assert.Equal(t, "id=2,id=1,q=a=b", fs.Lookup("param").Value.String())
+	})
+	t.Run("invalid pair", func(t *testing.T) {
+		t.Parallel()
+		v := StringMultiMap()
+		err := v.Set("nope")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing '='")
+		assert.Equal(t, "", v.String())
+	})
+}
+
+func TestSecret(t *testing.T) {
+	t.Parallel()
+
+	t.Run("masks value", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(Secret(), "token", "")
+		err := fs.Parse([]string{"--token=hunter2"})
+		require.NoError(t, err)
+		assert.Equal(t, "*****", fs.Lookup("token").Value.String())
+		got := fs.Lookup("token").Value.(flag.Getter).Get().(SecretString)
+		assert.True(t, got.IsSet())
+		assert.Equal(t, "hunter2", got.Expose())
+		assert.Equal(t, "*****", got.String())
+		assert.Equal(t, "token=*****", fmt.Sprintf("token=%v", got))
+		assert.NotContains(t, fmt.Sprintf("%#v %q %s", got, got, got), "hunter2")
+		assert.Equal(t, "*****", got.LogValue().String())
+	})
+	t.Run("unset", func(t *testing.T) {
+		t.Parallel()
+		v := Secret()
+		assert.Equal(t, "", v.String())
+		got := v.(flag.Getter).Get().(SecretString)
+		assert.False(t, got.IsSet())
+		assert.Equal(t, "", got.Expose())
+	})
+}
+
+func TestSecretSource(t *testing.T) {
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("FLAGTYPE_TEST_TOKEN", "from-env")
+		v := SecretSource()
+		require.NoError(t, v.Set("env://FLAGTYPE_TEST_TOKEN"))
+		assert.Equal(t, "from-env", v.(flag.Getter).Get().(SecretString).Expose())
+		assert.Equal(t, "env://FLAGTYPE_TEST_TOKEN", v.String())
+	})
+	t.Run("env not set", func(t *testing.T) {
+		v := SecretSource()
+		err := v.Set("env://FLAGTYPE_TEST_MISSING")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not set")
+		err = v.Set("env://")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing variable name")
+	})
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+		v := SecretSource()
+		require.NoError(t, v.Set("file://"+path))
+		assert.Equal(t, "from-file", v.(flag.Getter).Get().(SecretString).Expose())
+		assert.Equal(t, "file://"+path, v.String())
+	})
+	t.Run("file missing", func(t *testing.T) {
+		v := SecretSource()
+		err := v.Set("file://" + filepath.Join(t.TempDir(), "missing"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read secret")
+	})
+	t.Run("literal", func(t *testing.T) {
+		v := SecretSource()
+		require.NoError(t, v.Set("hunter2"))
+		assert.Equal(t, "hunter2", v.(flag.Getter).Get().(SecretString).Expose())
+		assert.Equal(t, "*****", v.String())
+	})
+}
+
+func TestRegexpSlice(t *testing.T) {
+	t.Parallel()
+
+	t.Run("multiple patterns", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(RegexpSlice(), "exclude", "")
+		err := fs.Parse([]string{"--exclude=foo", "--exclude=ba.*r"})
+		require.NoError(t, err)
+		got := fs.Lookup("exclude").Value.(flag.Getter).Get().([]*regexp.Regexp)
+		require.Len(t, got, 2)
+		assert.True(t, got[0].MatchString("xfoox"))
+		assert.True(t, got[1].MatchString("baaar"))
+		assert.Equal(t, "foo,ba.*r", fs.Lookup("exclude").Value.String())
+	})
+	t.Run("invalid pattern", func(t *testing.T) {
+		t.Parallel()
+		v := RegexpSlice()
+		require.NoError(t, v.Set("ok"))
+		require.Error(t, v.Set("[invalid"))
+		assert.Len(t, v.(flag.Getter).Get(), 1)
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := RegexpSlice()
+		assert.Equal(t, "", v.String())
+		assert.Nil(t, v.(flag.Getter).Get())
+	})
+}
+
+func TestFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parses typed value", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(Func(netip.ParseAddrPort), "listen", "")
+		err := fs.Parse([]string{"--listen=127.0.0.1:8080"})
+		require.NoError(t, err)
+		got := fs.Lookup("listen").Value.(flag.Getter).Get().(netip.AddrPort)
+		assert.Equal(t, uint16(8080), got.Port())
+		assert.Equal(t, "127.0.0.1:8080", fs.Lookup("listen").Value.String())
+	})
+	t.Run("parse error", func(t *testing.T) {
+		t.Parallel()
+		v := Func(time.ParseDuration)
+		require.NoError(t, v.Set("1s"))
+		require.Error(t, v.Set("soon"))
+		assert.Equal(t, time.Second, v.(flag.Getter).Get())
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		v := Func(time.ParseDuration)
+		assert.Equal(t, "", v.String())
+		assert.Equal(t, time.Duration(0), v.(flag.Getter).Get())
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	noSpaces := func(s string) error {
+		if strings.Contains(s, " ") {
+			return fmt.Errorf("must not contain spaces")
+		}
+		return nil
+	}
+	t.Run("passes valid values through", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(Validate(StringSlice(), noSpaces), "tag", "")
+		err := fs.Parse([]string{"--tag=a", "--tag=b"})
+		require.NoError(t, err)
+		got := fs.Lookup("tag").Value.(flag.Getter).Get().([]string)
+		assert.Equal(t, []string{"a", "b"}, got)
+	})
+	t.Run("rejects invalid values", func(t *testing.T) {
+		t.Parallel()
+		v := Validate(StringSlice(), noSpaces)
+		require.NoError(t, v.Set("a"))
+		err := v.Set("a b")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must not contain spaces")
+		assert.Equal(t, []string{"a"}, v.(flag.Getter).Get())
+	})
+	t.Run("keeps bool flag behavior", func(t *testing.T) {
+		t.Parallel()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(Validate(Count(), noSpaces), "v", "")
+		err := fs.Parse([]string{"-v", "-v", "arg"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, fs.Lookup("v").Value.(flag.Getter).Get())
+		assert.Equal(t, []string{"arg"}, fs.Args())
+	})
+}
+
+func TestExpandedString(t *testing.T) {
+	t.Setenv("FLAGTYPE_TEST_HOME", "/home/gopher")
+	t.Setenv("FLAGTYPE_TEST_EMPTY", "")
+
+	t.Run("expands variables", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(ExpandedString(), "path", "")
+		err := fs.Parse([]string{"--path=$FLAGTYPE_TEST_HOME/data/${FLAGTYPE_TEST_HOME}"})
+		require.NoError(t, err)
+		got := fs.Lookup("path").Value.(flag.Getter).Get().(string)
+		assert.Equal(t, "/home/gopher/data//home/gopher", got)
+	})
+	t.Run("unset expands to empty", func(t *testing.T) {
+		v := ExpandedString()
+		require.NoError(t, v.Set("a${FLAGTYPE_TEST_UNSET}b"))
+		assert.Equal(t, "ab", v.String())
+	})
+	t.Run("error on unset", func(t *testing.T) {
+		v := ExpandedString(WithErrorOnUnset())
+		err := v.Set("$FLAGTYPE_TEST_UNSET/$FLAGTYPE_TEST_HOME/$FLAGTYPE_TEST_OTHER")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "environment variable not set: FLAGTYPE_TEST_UNSET, FLAGTYPE_TEST_OTHER")
+		require.NoError(t, v.Set("$FLAGTYPE_TEST_EMPTY/x"))
+		assert.Equal(t, "/x", v.String())
+	})
+	t.Run("no references", func(t *testing.T) {
+		v := ExpandedString(WithErrorOnUnset())
+		require.NoError(t, v.Set("plain"))
+		assert.Equal(t, "plain", v.(flag.Getter).Get())
+	})
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+
+	describe := func(v flag.Value) string {
+		d, ok := v.(interface{ Describe() string })
+		require.True(t, ok, "%T does not implement Describe", v)
+		return d.Describe()
+	}
+	assert.Equal(t, "one of: json, yaml", describe(Enum("json", "yaml")))
+	assert.Equal(t, "one of: json, yaml", describe(EnumDefault("json", []string{"json", "yaml"})))
+	assert.Equal(t, "one of: net, fs", describe(EnumSlice("net", "fs")))
+	assert.Equal(t, "range: 1-8", describe(IntRange(1, 8)))
+	assert.Equal(t, "range: 0-0.5", describe(Float64Range(0, 0.5)))
+	assert.Equal(t, "range: 1s-1m0s", describe(DurationRange(time.Second, time.Minute)))
+	assert.Equal(t, "range: 1-65535", describe(Port()))
+}
+
+func TestCloneValue(t *testing.T) {
+	t.Parallel()
+
+	noop := func(string) error { return nil }
+	for _, tc := range []struct {
+		name   string
+		v      flag.Value
+		first  string
+		second string
+	}{
+		{"string slice", StringSlice(), "a", "b"},
+		{"int slice", IntSlice(WithSeparator(",")), "1", "2"},
+		{"enum slice", EnumSlice("a", "b"), "a", "b"},
+		{"key value slice", KeyValueSlice(), "k=a", "k=b"},
+		{"string map", StringMap(), "k=a", "k=b"},
+		{"string multi map", StringMultiMap(), "k=a", "k=b"},
+		{"validated slice", Validate(StringSlice(), noop), "a", "b"},
+		{"func", Func(strconv.Atoi), "1", "2"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.NoError(t, tc.v.Set(tc.first))
+			want := tc.v.String()
+			clone := CloneValue(tc.v)
+			assert.IsType(t, tc.v, clone)
+			assert.Equal(t, want, clone.String())
+			require.NoError(t, clone.Set(tc.second))
+			assert.Equal(t, want, tc.v.String(), "setting the clone changed the original")
+			require.NoError(t, tc.v.Set(tc.second))
+			assert.Equal(t, tc.v.String(), clone.String())
+		})
+	}
+	t.Run("enum slice keeps describe", func(t *testing.T) {
+		t.Parallel()
+		clone := CloneValue(EnumSlice("net", "fs"))
+		assert.Equal(t, "one of: net, fs", clone.(interface{ Describe() string }).Describe())
+	})
+}
+
 // nopWriter discards all writes, used to suppress flag.FlagSet error output in tests.
 type nopWriter struct{}
 
@@ -0,0 +1,96 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+)
+
+type value[T any] struct {
+	val   T
+	set   bool
+	parse func(string) (T, error)
+}
+
+// Func returns a [flag.Value] that converts the flag value with parse, for one-off custom types
+// that don't warrant a full [flag.Value] implementation:
+//
+//	f.Var(flagtype.Func(strconv.ParseBool), "dry-run", "preview changes")
+//	f.Var(flagtype.Func(mail.ParseAddress), "from", "sender address")
+//
+// If parse returns an error, it is reported as the flag's parse error and the value is left
+// unchanged. String formats the parsed value with [fmt.Sprint].
+//
+// Use [cli.GetFlag] with type T to retrieve the value.
+func Func[T any](parse func(string) (T, error)) flag.Value {
+	return &value[T]{parse: parse}
+}
+
+func (v *value[T]) String() string {
+	if !v.set {
+		return ""
+	}
+	return fmt.Sprint(v.val)
+}
+
+func (v *value[T]) Set(s string) error {
+	val, err := v.parse(s)
+	if err != nil {
+		return err
+	}
+	v.val, v.set = val, true
+	return nil
+}
+
+func (v *value[T]) Get() any {
+	return v.val
+}
+
+func (v *value[T]) Clone() flag.Value {
+	clone := *v
+	return &clone
+}
+
+type validatedValue struct {
+	flag.Value
+	check func(string) error
+}
+
+// Validate wraps v so that check runs on each raw value before it is passed to v.Set. If check
+// returns an error, v is not modified and the error is reported as the flag's parse error:
+//
+//	f.Var(flagtype.Validate(flagtype.StringSlice(), validateTag), "tag", "add a tag")
+//
+// The wrapped value keeps the behavior of v, including [flag.Getter] and boolean flags, and help
+// output shows the type of v.
+func Validate(v flag.Value, check func(string) error) flag.Value {
+	return &validatedValue{Value: v, check: check}
+}
+
+func (v *validatedValue) Set(s string) error {
+	if err := v.check(s); err != nil {
+		return err
+	}
+	return v.Value.Set(s)
+}
+
+func (v *validatedValue) Get() any {
+	if g, ok := v.Value.(flag.Getter); ok {
+		return g.Get()
+	}
+	return v.Value.String()
+}
+
+// Clone returns a copy of v wrapping a copy of the wrapped value. See [CloneValue].
+func (v *validatedValue) Clone() flag.Value {
+	return &validatedValue{Value: CloneValue(v.Value), check: v.check}
+}
+
+// Unwrap returns the wrapped value.
+func (v *validatedValue) Unwrap() flag.Value {
+	return v.Value
+}
+
+func (v *validatedValue) IsBoolFlag() bool {
+	b, ok := v.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
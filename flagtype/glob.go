@@ -0,0 +1,157 @@
+package flagtype
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type globValue struct {
+	pattern string
+	matches []string
+	expand  bool
+	base    string
+}
+
+// Glob returns a [flag.Value] that validates a glob pattern at parse time, like --include='*.go'.
+// Patterns use [path.Match] syntax per path segment, and a "**" segment matches any number of
+// directories, as in "src/**/*_test.go". Use [MatchGlob] to match names against the pattern.
+//
+// Use [cli.GetFlag] with type string to retrieve the pattern.
+func Glob() flag.Value {
+	return &globValue{}
+}
+
+// GlobFiles is like [Glob] but also expands the pattern against the file system at parse time, for
+// shells or config files that don't expand patterns themselves. A pattern that matches nothing
+// yields an empty list, not an error. A relative pattern is expanded in the directory set with
+// SetBaseDir, if any, and the matched paths include that directory.
+//
+// Use [cli.GetFlag] with type []string to retrieve the matched paths, in lexical order.
+func GlobFiles() flag.Value {
+	return &globValue{expand: true}
+}
+
+func (v *globValue) String() string {
+	return v.pattern
+}
+
+func (v *globValue) Set(s string) error {
+	if err := validateGlob(s); err != nil {
+		return fmt.Errorf("invalid glob pattern %q: %w", s, err)
+	}
+	if v.expand {
+		matches, err := expandGlob(filepath.ToSlash(resolvePath(v.base, s)))
+		if err != nil {
+			return fmt.Errorf("failed to expand glob pattern %q: %w", s, err)
+		}
+		v.matches = matches
+	}
+	v.pattern = s
+	return nil
+}
+
+func (v *globValue) Get() any {
+	if v.expand {
+		return v.matches
+	}
+	return v.pattern
+}
+
+// SetBaseDir sets the directory that relative patterns are expanded in. The cli package calls it
+// with the directory from its RunOptions before parsing.
+func (v *globValue) SetBaseDir(dir string) {
+	v.base = dir
+}
+
+// MatchGlob reports whether name matches the pattern accepted by [Glob]: each "/"-separated
+// segment is matched with [path.Match], and a "**" segment matches zero or more segments. Invalid
+// patterns never match.
+func MatchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
+
+func validateGlob(pattern string) error {
+	if pattern == "" {
+		return errors.New("pattern must not be empty")
+	}
+	for _, seg := range strings.Split(pattern, "/") {
+		if _, err := path.Match(seg, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandGlob returns the paths matching pattern. Patterns without "**" use [filepath.Glob];
+// otherwise the tree below the pattern's literal prefix is walked.
+func expandGlob(pattern string) ([]string, error) {
+	segments := strings.Split(pattern, "/")
+	hasDoubleStar := false
+	for _, seg := range segments {
+		if seg == "**" {
+			hasDoubleStar = true
+			break
+		}
+	}
+	if !hasDoubleStar {
+		return filepath.Glob(filepath.FromSlash(pattern))
+	}
+	// Walk from the longest leading run of segments without wildcards.
+	var base []string
+	for _, seg := range segments {
+		if strings.ContainsAny(seg, `*?[\`) {
+			break
+		}
+		base = append(base, seg)
+	}
+	root := strings.Join(base, "/")
+	if root == "" {
+		root = "."
+		if strings.HasPrefix(pattern, "/") {
+			root = "/"
+		}
+	}
+	var matches []string
+	err := filepath.WalkDir(filepath.FromSlash(root), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == filepath.FromSlash(root) {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if MatchGlob(pattern, filepath.ToSlash(p)) {
+			matches = append(matches, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
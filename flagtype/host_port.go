@@ -0,0 +1,101 @@
+package flagtype
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// HostAndPort is a host and port pair parsed by [HostPort].
+type HostAndPort struct {
+	// Host is a hostname or IP address, without brackets for IPv6. It is empty for values like
+	// ":8080", which conventionally mean all interfaces.
+	Host string
+	Port int
+}
+
+// String returns the pair in host:port form, bracketing IPv6 hosts.
+func (h HostAndPort) String() string {
+	return net.JoinHostPort(h.Host, strconv.Itoa(h.Port))
+}
+
+type hostPortValue struct {
+	hp  HostAndPort
+	set bool
+}
+
+// HostPort returns a [flag.Value] that parses a host:port pair, like localhost:8080, :8080, or
+// [::1]:8080 for IPv6. The port must be a number between 1 and 65535.
+//
+// Use [cli.GetFlag] with type [HostAndPort] to retrieve the value.
+func HostPort() flag.Value {
+	return &hostPortValue{}
+}
+
+func (v *hostPortValue) String() string {
+	if !v.set {
+		return ""
+	}
+	return v.hp.String()
+}
+
+func (v *hostPortValue) Set(s string) error {
+	host, portStr, err := net.SplitHostPort(s)
+	if err != nil {
+		return fmt.Errorf("invalid host:port %q", s)
+	}
+	port, err := parsePort(portStr)
+	if err != nil {
+		return fmt.Errorf("invalid host:port %q: %w", s, err)
+	}
+	v.hp = HostAndPort{Host: host, Port: port}
+	v.set = true
+	return nil
+}
+
+func (v *hostPortValue) Get() any {
+	return v.hp
+}
+
+type portValue struct {
+	port int
+}
+
+// Port returns a [flag.Value] that parses a TCP or UDP port number between 1 and 65535.
+//
+// Use [cli.GetFlag] with type int to retrieve the value.
+func Port() flag.Value {
+	return &portValue{}
+}
+
+func (v *portValue) String() string {
+	return strconv.Itoa(v.port)
+}
+
+func (v *portValue) Set(s string) error {
+	port, err := parsePort(s)
+	if err != nil {
+		return fmt.Errorf("invalid port %q: %w", s, err)
+	}
+	v.port = port
+	return nil
+}
+
+func (v *portValue) Get() any {
+	return v.port
+}
+
+// Describe shows the valid port range for help output.
+func (v *portValue) Describe() string {
+	return "range: 1-65535"
+}
+
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, errors.New("port must be a number between 1 and 65535")
+	}
+	return port, nil
+}
@@ -0,0 +1,73 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+	"net/netip"
+)
+
+type ipValue struct {
+	addr netip.Addr
+}
+
+// IP returns a [flag.Value] that parses an IPv4 or IPv6 address, like 192.168.1.10 or ::1.
+//
+// Use [cli.GetFlag] with type netip.Addr to retrieve the value. The zero [netip.Addr] (for which
+// IsValid reports false) means the flag was not set.
+func IP() flag.Value {
+	return &ipValue{}
+}
+
+func (v *ipValue) String() string {
+	if !v.addr.IsValid() {
+		return ""
+	}
+	return v.addr.String()
+}
+
+func (v *ipValue) Set(s string) error {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return fmt.Errorf("invalid IP address %q", s)
+	}
+	v.addr = addr
+	return nil
+}
+
+func (v *ipValue) Get() any {
+	return v.addr
+}
+
+type cidrValue struct {
+	prefix netip.Prefix
+}
+
+// CIDR returns a [flag.Value] that parses an IP network in CIDR notation, like 10.0.0.0/8 or
+// 2001:db8::/32. The address is kept as written; use [netip.Prefix.Masked] to get the network
+// address.
+//
+// Use [cli.GetFlag] with type netip.Prefix to retrieve the value. The zero [netip.Prefix] (for
+// which IsValid reports false) means the flag was not set.
+func CIDR() flag.Value {
+	return &cidrValue{}
+}
+
+func (v *cidrValue) String() string {
+	if !v.prefix.IsValid() {
+		return ""
+	}
+	return v.prefix.String()
+}
+
+func (v *cidrValue) Set(s string) error {
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR %q", s)
+	}
+	v.prefix = prefix
+	return nil
+}
+
+func (v *cidrValue) Get() any {
+	return v.prefix
+}
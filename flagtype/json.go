@@ -0,0 +1,49 @@
+package flagtype
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+)
+
+type jsonValue[T any] struct {
+	raw string
+	val T
+}
+
+// JSON returns a [flag.Value] that parses the flag value as a JSON object, like
+// --labels='{"env":"prod"}'. Invalid JSON is rejected at parse time.
+//
+// Use [cli.GetFlag] with type map[string]any to retrieve the value.
+func JSON() flag.Value {
+	return &jsonValue[map[string]any]{}
+}
+
+// JSONInto is like [JSON] but unmarshals the value into a new T with [json.Unmarshal], so the flag
+// can be decoded straight into a typed struct:
+//
+//	f.Var(flagtype.JSONInto[Config](), "config", "inline JSON configuration")
+//
+// Use [cli.GetFlag] with type T to retrieve the value.
+func JSONInto[T any]() flag.Value {
+	return &jsonValue[T]{}
+}
+
+func (v *jsonValue[T]) String() string {
+	return v.raw
+}
+
+func (v *jsonValue[T]) Set(s string) error {
+	// Decode into a fresh value so a repeated flag replaces, rather than merges into, the previous
+	// one.
+	var val T
+	if err := json.Unmarshal([]byte(s), &val); err != nil {
+		return fmt.Errorf("invalid JSON %q: %w", s, err)
+	}
+	v.raw, v.val = s, val
+	return nil
+}
+
+func (v *jsonValue[T]) Get() any {
+	return v.val
+}
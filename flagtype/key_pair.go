@@ -0,0 +1,62 @@
+package flagtype
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+type keyPairValue struct {
+	raw  string
+	cert *tls.Certificate
+	base string
+}
+
+// KeyPair returns a [flag.Value] that loads a PEM-encoded TLS certificate and private key at parse
+// time with [tls.LoadX509KeyPair]. The value is the certificate path and the key path separated by a
+// comma, like --tls=server.crt,server.key. Missing files, invalid PEM data, and mismatched keys are
+// reported before the command runs, so servers fail before binding a port. Relative paths are read
+// from the directory set with SetBaseDir, if any.
+//
+// Use [cli.GetFlag] with type *tls.Certificate to retrieve the value. It is nil if the flag was not
+// set.
+func KeyPair() flag.Value {
+	return &keyPairValue{}
+}
+
+func (v *keyPairValue) String() string {
+	return v.raw
+}
+
+func (v *keyPairValue) Set(s string) error {
+	certFile, keyFile, ok := strings.Cut(s, ",")
+	if !ok || certFile == "" || keyFile == "" {
+		return fmt.Errorf("invalid key pair %q, must be <cert-file>,<key-file>", s)
+	}
+	cert, err := tls.LoadX509KeyPair(resolvePath(v.base, certFile), resolvePath(v.base, keyFile))
+	if err != nil {
+		return fmt.Errorf("invalid key pair %q: %w", s, err)
+	}
+	v.raw, v.cert = s, &cert
+	return nil
+}
+
+func (v *keyPairValue) Get() any {
+	return v.cert
+}
+
+// SetBaseDir sets the directory that relative paths are read from. The cli package calls it with
+// the directory from its RunOptions before parsing.
+func (v *keyPairValue) SetBaseDir(dir string) {
+	v.base = dir
+}
+
+func (v *keyPairValue) Clone() flag.Value {
+	clone := *v
+	if v.cert != nil {
+		cert := *v.cert
+		clone.cert = &cert
+	}
+	return &clone
+}
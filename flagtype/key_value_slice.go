@@ -0,0 +1,57 @@
+package flagtype
+
+import (
+	"flag"
+	"slices"
+	"strings"
+)
+
+// KV is a single key=value pair collected by [KeyValueSlice].
+type KV struct {
+	Key   string
+	Value string
+}
+
+// String returns the pair in key=value form.
+func (kv KV) String() string {
+	return kv.Key + "=" + kv.Value
+}
+
+type keyValueSliceValue struct {
+	kvs []KV
+}
+
+// KeyValueSlice returns a [flag.Value] that parses key=value pairs into a slice, preserving the
+// order they were given in. Unlike [StringMap], repeated keys are kept rather than overwritten,
+// which suits flags like --header where both ordering and duplicates matter. The value is split on
+// the first "=" character, so values may contain additional "=" characters.
+//
+// Use [cli.GetFlag] with type []KV to retrieve the value.
+func KeyValueSlice() flag.Value {
+	return &keyValueSliceValue{}
+}
+
+func (v *keyValueSliceValue) String() string {
+	pairs := make([]string, 0, len(v.kvs))
+	for _, kv := range v.kvs {
+		pairs = append(pairs, kv.String())
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v *keyValueSliceValue) Set(s string) error {
+	key, value, err := parseKeyValue(s)
+	if err != nil {
+		return err
+	}
+	v.kvs = append(v.kvs, KV{Key: key, Value: value})
+	return nil
+}
+
+func (v *keyValueSliceValue) Get() any {
+	return v.kvs
+}
+
+func (v *keyValueSliceValue) Clone() flag.Value {
+	return &keyValueSliceValue{kvs: slices.Clone(v.kvs)}
+}
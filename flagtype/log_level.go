@@ -0,0 +1,43 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+type logLevelValue struct {
+	level slog.Level
+}
+
+// LogLevel returns a [flag.Value] that parses a log level: debug, info, warn (or warning), or
+// error, case-insensitively and with an optional offset such as "warn+2", or a plain number like
+// -4. The value starts at info, so it can be passed straight to [slog.HandlerOptions].
+//
+// Use [cli.GetFlag] with type slog.Level to retrieve the value.
+func LogLevel() flag.Value {
+	return &logLevelValue{level: slog.LevelInfo}
+}
+
+func (v *logLevelValue) String() string {
+	return strings.ToLower(v.level.String())
+}
+
+func (v *logLevelValue) Set(s string) error {
+	var level slog.Level
+	if n, err := strconv.Atoi(s); err == nil {
+		level = slog.Level(n)
+	} else if strings.EqualFold(s, "warning") {
+		level = slog.LevelWarn
+	} else if err := level.UnmarshalText([]byte(s)); err != nil {
+		return fmt.Errorf("invalid log level %q, must be one of: debug, info, warn, error", s)
+	}
+	v.level = level
+	return nil
+}
+
+func (v *logLevelValue) Get() any {
+	return v.level
+}
@@ -0,0 +1,79 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+// rangeValue is the shared implementation of numeric flags bounded by an inclusive range.
+type rangeValue[T int | float64] struct {
+	n        T
+	set      bool
+	min, max T
+	parse    func(string) (T, error)
+	format   func(T) string
+}
+
+func newRangeValue[T int | float64](min, max T, parse func(string) (T, error), format func(T) string) rangeValue[T] {
+	if min > max {
+		panic(fmt.Sprintf("flagtype: range minimum %s is greater than maximum %s", format(min), format(max)))
+	}
+	return rangeValue[T]{min: min, max: max, parse: parse, format: format}
+}
+
+// String returns "" until the value is set, since the zero value may be outside the range and
+// should not be shown as a default in help output.
+func (v *rangeValue[T]) String() string {
+	if !v.set || v.format == nil {
+		return ""
+	}
+	return v.format(v.n)
+}
+
+func (v *rangeValue[T]) Set(s string) error {
+	n, err := v.parse(s)
+	if err != nil {
+		return err
+	}
+	if n < v.min || n > v.max {
+		return fmt.Errorf("invalid value %q, must be between %s and %s", s, v.format(v.min), v.format(v.max))
+	}
+	v.n, v.set = n, true
+	return nil
+}
+
+func (v *rangeValue[T]) Get() any {
+	return v.n
+}
+
+// Describe shows the bounds for help output.
+func (v *rangeValue[T]) Describe() string {
+	return "range: " + v.format(v.min) + "-" + v.format(v.max)
+}
+
+type intRangeValue struct {
+	rangeValue[int]
+}
+
+// IntRange returns a [flag.Value] that parses an integer and rejects values outside [min, max]
+// (inclusive) at parse time, like --threads between 1 and runtime.NumCPU(). Until the flag is set,
+// the value is zero, which is not checked against the bounds, and help output shows no default.
+// IntRange panics if min is greater than max.
+//
+// Use [cli.GetFlag] with type int to retrieve the value.
+func IntRange(min, max int) flag.Value {
+	return &intRangeValue{newRangeValue(min, max, parseInt, strconv.Itoa)}
+}
+
+type float64RangeValue struct {
+	rangeValue[float64]
+}
+
+// Float64Range is like [IntRange] but parses a floating-point number, like --sample-rate between
+// 0 and 1.
+//
+// Use [cli.GetFlag] with type float64 to retrieve the value.
+func Float64Range(min, max float64) flag.Value {
+	return &float64RangeValue{newRangeValue(min, max, parseFloat64, formatFloat64)}
+}
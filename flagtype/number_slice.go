@@ -0,0 +1,88 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type intSliceValue struct {
+	sliceValue[int]
+}
+
+// IntSlice returns a [flag.Value] that collects integers into a slice. Each time the flag is set,
+// the value is parsed and appended, like --port=80 --port=443. Use [WithSeparator] to also accept
+// several values per occurrence, like --port=80,443.
+//
+// Use [cli.GetFlag] with type []int to retrieve the value.
+func IntSlice(opts ...SliceOption) flag.Value {
+	return &intSliceValue{newSliceValue(parseInt, strconv.Itoa, opts)}
+}
+
+type int64SliceValue struct {
+	sliceValue[int64]
+}
+
+// Int64Slice is like [IntSlice] but collects 64-bit integers.
+//
+// Use [cli.GetFlag] with type []int64 to retrieve the value.
+func Int64Slice(opts ...SliceOption) flag.Value {
+	return &int64SliceValue{newSliceValue(parseInt64, formatInt64, opts)}
+}
+
+type float64SliceValue struct {
+	sliceValue[float64]
+}
+
+// Float64Slice is like [IntSlice] but collects floating-point numbers, like --ratio=0.5
+// --ratio=1.5.
+//
+// Use [cli.GetFlag] with type []float64 to retrieve the value.
+func Float64Slice(opts ...SliceOption) flag.Value {
+	return &float64SliceValue{newSliceValue(parseFloat64, formatFloat64, opts)}
+}
+
+func parseInt(s string) (int, error) {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 0, strconv.IntSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer %q", s)
+	}
+	return int(n), nil
+}
+
+func parseInt64(s string) (int64, error) {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer %q", s)
+	}
+	return n, nil
+}
+
+func formatInt64(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func parseFloat64(s string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return f, nil
+}
+
+func formatFloat64(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func (v *intSliceValue) Clone() flag.Value {
+	return &intSliceValue{v.sliceValue.clone()}
+}
+
+func (v *int64SliceValue) Clone() flag.Value {
+	return &int64SliceValue{v.sliceValue.clone()}
+}
+
+func (v *float64SliceValue) Clone() flag.Value {
+	return &float64SliceValue{v.sliceValue.clone()}
+}
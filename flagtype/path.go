@@ -0,0 +1,107 @@
+package flagtype
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type pathKind int
+
+const (
+	anyPath pathKind = iota
+	existingFile
+	existingDir
+)
+
+type pathValue struct {
+	p    string
+	kind pathKind
+	base string
+}
+
+// Path returns a [flag.Value] that converts the value to a clean, absolute path, resolved against
+// the current working directory or the directory set with SetBaseDir. The path does not need to
+// exist.
+//
+// Use [cli.GetFlag] with type string to retrieve the value.
+func Path() flag.Value {
+	return &pathValue{kind: anyPath}
+}
+
+// ExistingFile returns a [flag.Value] that requires the value to be the path of an existing file
+// (not a directory), checked at parse time so commands fail before doing any work. The path is kept
+// as given, unless it is relative and a directory was set with SetBaseDir, in which case it is
+// joined to that directory.
+//
+// Use [cli.GetFlag] with type string to retrieve the value.
+func ExistingFile() flag.Value {
+	return &pathValue{kind: existingFile}
+}
+
+// ExistingDir returns a [flag.Value] that requires the value to be the path of an existing
+// directory, checked at parse time. Relative paths are handled as in [ExistingFile].
+//
+// Use [cli.GetFlag] with type string to retrieve the value.
+func ExistingDir() flag.Value {
+	return &pathValue{kind: existingDir}
+}
+
+func (v *pathValue) String() string {
+	return v.p
+}
+
+func (v *pathValue) Set(s string) error {
+	if s == "" {
+		return errors.New("path must not be empty")
+	}
+	s = resolvePath(v.base, s)
+	switch v.kind {
+	case anyPath:
+		abs, err := filepath.Abs(s)
+		if err != nil {
+			return fmt.Errorf("invalid path %q: %w", s, err)
+		}
+		s = abs
+	case existingFile, existingDir:
+		info, err := os.Stat(s)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				if v.kind == existingDir {
+					return fmt.Errorf("directory %q does not exist", s)
+				}
+				return fmt.Errorf("file %q does not exist", s)
+			}
+			return err
+		}
+		if v.kind == existingFile && info.IsDir() {
+			return fmt.Errorf("%q is a directory, expected a file", s)
+		}
+		if v.kind == existingDir && !info.IsDir() {
+			return fmt.Errorf("%q is not a directory", s)
+		}
+	}
+	v.p = s
+	return nil
+}
+
+func (v *pathValue) Get() any {
+	return v.p
+}
+
+// SetBaseDir sets the directory that relative paths are resolved against. The cli package calls it
+// with the directory from its RunOptions before parsing.
+func (v *pathValue) SetBaseDir(dir string) {
+	v.base = dir
+}
+
+// resolvePath joins a relative path to base, if base is set.
+func resolvePath(base, path string) string {
+	if base == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(base, path)
+}
@@ -36,3 +36,21 @@ func (v *regexpValue) Set(s string) error {
 func (v *regexpValue) Get() any {
 	return v.re
 }
+
+type regexpSliceValue struct {
+	sliceValue[*regexp.Regexp]
+}
+
+// RegexpSlice returns a [flag.Value] that compiles each occurrence of the flag as a regular
+// expression and collects them into a slice, like --exclude=foo --exclude='ba.*r'. If a pattern is
+// invalid, an error is returned. Slice options are accepted, but [WithSeparator] is rarely useful
+// since the separator can't then appear in a pattern.
+//
+// Use [cli.GetFlag] with type []*regexp.Regexp to retrieve the value.
+func RegexpSlice(opts ...SliceOption) flag.Value {
+	return &regexpSliceValue{newSliceValue(regexp.Compile, (*regexp.Regexp).String, opts)}
+}
+
+func (v *regexpSliceValue) Clone() flag.Value {
+	return &regexpSliceValue{v.sliceValue.clone()}
+}
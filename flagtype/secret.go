@@ -0,0 +1,71 @@
+package flagtype
+
+import (
+	"flag"
+	"log/slog"
+)
+
+// secretMask is what a set secret prints as, regardless of its length.
+const secretMask = "*****"
+
+// SecretString holds a sensitive value parsed by [Secret]. Printing it with fmt, logging it with
+// slog, or including it in an error message shows a mask instead of the value. Call
+// [SecretString.Expose] to get the real value.
+type SecretString struct {
+	value string
+}
+
+// Expose returns the underlying secret value.
+func (s SecretString) Expose() string {
+	return s.value
+}
+
+// IsSet reports whether the secret holds a non-empty value.
+func (s SecretString) IsSet() bool {
+	return s.value != ""
+}
+
+// String returns "*****" for a non-empty secret and "" otherwise.
+func (s SecretString) String() string {
+	if s.value == "" {
+		return ""
+	}
+	return secretMask
+}
+
+// GoString masks the value for the %#v verb.
+func (s SecretString) GoString() string {
+	return "flagtype.SecretString(" + s.String() + ")"
+}
+
+// LogValue masks the value when logged with [log/slog].
+func (s SecretString) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+type secretValue struct {
+	s SecretString
+}
+
+// Secret returns a [flag.Value] for sensitive values like passwords and API tokens. Its String
+// method returns "*****" once a value is set, so the value never leaks into help output, error
+// messages, or debug dumps.
+//
+// Use [cli.GetFlag] with type [SecretString] to retrieve the value, then call
+// [SecretString.Expose] where the real value is needed.
+func Secret() flag.Value {
+	return &secretValue{}
+}
+
+func (v *secretValue) String() string {
+	return v.s.String()
+}
+
+func (v *secretValue) Set(s string) error {
+	v.s = SecretString{value: s}
+	return nil
+}
+
+func (v *secretValue) Get() any {
+	return v.s
+}
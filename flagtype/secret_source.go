@@ -0,0 +1,86 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type secretSourceValue struct {
+	source    string
+	s         SecretString
+	base      string
+	lookupEnv func(string) (string, bool)
+}
+
+// SecretSource returns a [flag.Value] that resolves a secret from where it is stored, so
+// credentials don't have to appear directly in argv. The value is resolved at parse time:
+//
+//   - env://NAME reads the environment variable NAME, which must be set, with the function set
+//     with SetLookupEnv, if any
+//   - file:///path/to/file reads the file, with one trailing newline removed; a relative path,
+//     like file://token.txt, is read from the directory set with SetBaseDir, if any
+//   - anything else is used as the literal secret
+//
+// String returns the env:// or file:// reference, which is safe to show in help and error output,
+// and "*****" for a literal secret.
+//
+// Use [cli.GetFlag] with type [SecretString] to retrieve the value.
+func SecretSource() flag.Value {
+	return &secretSourceValue{}
+}
+
+func (v *secretSourceValue) String() string {
+	if v.source != "" {
+		return v.source
+	}
+	return v.s.String()
+}
+
+func (v *secretSourceValue) Set(s string) error {
+	switch {
+	case strings.HasPrefix(s, "env://"):
+		name := strings.TrimPrefix(s, "env://")
+		if name == "" {
+			return fmt.Errorf("invalid secret source %q: missing variable name", s)
+		}
+		val, ok := lookupEnv(v.lookupEnv, name)
+		if !ok {
+			return fmt.Errorf("invalid secret source %q: environment variable %s is not set", s, name)
+		}
+		v.source, v.s = s, SecretString{value: val}
+	case strings.HasPrefix(s, "file://"):
+		path := strings.TrimPrefix(s, "file://")
+		if path == "" {
+			return fmt.Errorf("invalid secret source %q: missing file path", s)
+		}
+		data, err := os.ReadFile(resolvePath(v.base, path))
+		if err != nil {
+			return fmt.Errorf("failed to read secret from %q: %w", path, err)
+		}
+		val := strings.TrimSuffix(string(data), "\n")
+		val = strings.TrimSuffix(val, "\r")
+		v.source, v.s = s, SecretString{value: val}
+	default:
+		v.source, v.s = "", SecretString{value: s}
+	}
+	return nil
+}
+
+func (v *secretSourceValue) Get() any {
+	return v.s
+}
+
+// SetBaseDir sets the directory that relative file:// paths are read from. The cli package calls
+// it with the directory from its RunOptions before parsing.
+func (v *secretSourceValue) SetBaseDir(dir string) {
+	v.base = dir
+}
+
+// SetLookupEnv sets the function that env:// references are read with, or restores [os.LookupEnv]
+// if lookupEnv is nil. The cli package calls it with the LookupEnv from its RunOptions before
+// parsing.
+func (v *secretSourceValue) SetLookupEnv(lookupEnv func(string) (string, bool)) {
+	v.lookupEnv = lookupEnv
+}
@@ -0,0 +1,198 @@
+package flagtype
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version parsed by [Semver], following https://semver.org/spec/v2.0.0.html.
+type Version struct {
+	Major, Minor, Patch uint64
+	// Prerelease is the dot-separated pre-release identifiers, like "rc.1", without the leading
+	// "-". Empty for a release version.
+	Prerelease string
+	// Build is the dot-separated build metadata, like "20260102.abc123", without the leading "+".
+	// It is ignored when comparing versions.
+	Build string
+}
+
+// String returns the version in SemVer form, without a leading "v".
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare returns -1, 0, or +1 depending on whether v has lower, equal, or higher precedence than
+// w, following the SemVer precedence rules. Build metadata is ignored.
+func (v Version) Compare(w Version) int {
+	if c := compareUint(v.Major, w.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, w.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, w.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.Prerelease, w.Prerelease)
+}
+
+// LessThan reports whether v has lower precedence than w.
+func (v Version) LessThan(w Version) bool {
+	return v.Compare(w) < 0
+}
+
+type semverValue struct {
+	v   Version
+	set bool
+}
+
+// Semver returns a [flag.Value] that parses a semantic version, like 1.2.3, v2.0.0-rc.1, or
+// 1.0.0+build.5. A leading "v" is accepted and dropped.
+//
+// Use [cli.GetFlag] with type [Version] to retrieve the value.
+func Semver() flag.Value {
+	return &semverValue{}
+}
+
+func (v *semverValue) String() string {
+	if !v.set {
+		return ""
+	}
+	return v.v.String()
+}
+
+func (v *semverValue) Set(s string) error {
+	ver, err := parseSemver(s)
+	if err != nil {
+		return fmt.Errorf("invalid semantic version %q: %w", s, err)
+	}
+	v.v, v.set = ver, true
+	return nil
+}
+
+func (v *semverValue) Get() any {
+	return v.v
+}
+
+func parseSemver(s string) (Version, error) {
+	var ver Version
+	rest := strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		rest, ver.Build = rest[:i], rest[i+1:]
+		if err := checkIdentifiers(ver.Build, false); err != nil {
+			return Version{}, fmt.Errorf("build metadata: %w", err)
+		}
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		rest, ver.Prerelease = rest[:i], rest[i+1:]
+		if err := checkIdentifiers(ver.Prerelease, true); err != nil {
+			return Version{}, fmt.Errorf("pre-release: %w", err)
+		}
+	}
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return Version{}, errors.New("must be MAJOR.MINOR.PATCH")
+	}
+	nums := make([]uint64, 3)
+	for i, part := range parts {
+		if !isNumeric(part) || (len(part) > 1 && part[0] == '0') {
+			return Version{}, fmt.Errorf("%q is not a valid version number", part)
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return Version{}, fmt.Errorf("%q is not a valid version number", part)
+		}
+		nums[i] = n
+	}
+	ver.Major, ver.Minor, ver.Patch = nums[0], nums[1], nums[2]
+	return ver, nil
+}
+
+// checkIdentifiers validates dot-separated identifiers made of [0-9A-Za-z-]. Numeric pre-release
+// identifiers must not have leading zeros.
+func checkIdentifiers(s string, prerelease bool) error {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return errors.New("empty identifier")
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return fmt.Errorf("invalid character %q in %q", r, id)
+			}
+		}
+		if prerelease && isNumeric(id) && len(id) > 1 && id[0] == '0' {
+			return fmt.Errorf("numeric identifier %q has a leading zero", id)
+		}
+	}
+	return nil
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparePrerelease compares pre-release strings. A release (empty pre-release) has higher
+// precedence than any pre-release. Identifiers are compared left to right: numeric identifiers
+// numerically, others lexically, and numeric ones always sort before alphanumeric ones. A longer set
+// of identifiers wins when all preceding identifiers are equal.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, y := as[i], bs[i]
+		if x == y {
+			continue
+		}
+		xNum, yNum := isNumeric(x), isNumeric(y)
+		switch {
+		case xNum && yNum:
+			// Compare by length first to avoid overflowing on very long numbers.
+			if len(x) != len(y) {
+				return compareUint(uint64(len(x)), uint64(len(y)))
+			}
+			return strings.Compare(x, y)
+		case xNum:
+			return -1
+		case yNum:
+			return 1
+		default:
+			return strings.Compare(x, y)
+		}
+	}
+	return compareUint(uint64(len(as)), uint64(len(bs)))
+}
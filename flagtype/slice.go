@@ -0,0 +1,120 @@
+package flagtype
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SliceOption configures repeatable slice flags such as [IntSlice].
+type SliceOption func(*sliceOptions)
+
+type sliceOptions struct {
+	sep        string
+	duplicates DuplicatePolicy
+}
+
+// WithSeparator splits each occurrence of the flag on sep, so --port=80,443 adds two values.
+// Whitespace around each value is trimmed and empty values are skipped. The flag can still be
+// repeated, and values from every occurrence are collected in order.
+func WithSeparator(sep string) SliceOption {
+	return func(o *sliceOptions) {
+		o.sep = sep
+	}
+}
+
+// DuplicatePolicy controls what a slice flag does when the same value is provided more than once.
+type DuplicatePolicy int
+
+const (
+	// AllowDuplicates keeps every value, including repeats. This is the default.
+	AllowDuplicates DuplicatePolicy = iota
+	// IgnoreDuplicates keeps only the first occurrence of each value.
+	IgnoreDuplicates
+	// RejectDuplicates returns an error when a value is provided more than once.
+	RejectDuplicates
+)
+
+// WithDuplicates sets how repeated values are handled. See [DuplicatePolicy].
+func WithDuplicates(p DuplicatePolicy) SliceOption {
+	return func(o *sliceOptions) {
+		o.duplicates = p
+	}
+}
+
+// sliceValue is the shared implementation of repeatable flags that collect parsed values of type T.
+type sliceValue[T any] struct {
+	vals       []T
+	sep        string
+	duplicates DuplicatePolicy
+	parse      func(string) (T, error)
+	format     func(T) string
+}
+
+func newSliceValue[T any](parse func(string) (T, error), format func(T) string, opts []SliceOption) sliceValue[T] {
+	var o sliceOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return sliceValue[T]{sep: o.sep, duplicates: o.duplicates, parse: parse, format: format}
+}
+
+func (v *sliceValue[T]) String() string {
+	parts := make([]string, 0, len(v.vals))
+	for _, val := range v.vals {
+		parts = append(parts, v.format(val))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (v *sliceValue[T]) Set(s string) error {
+	parts := []string{s}
+	if v.sep != "" {
+		parts = parts[:0]
+		for _, part := range strings.Split(s, v.sep) {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
+	// Parse every part before appending, so an invalid occurrence leaves the value unchanged.
+	// Duplicates are detected by formatted value, which works for any T.
+	seen := make(map[string]bool)
+	if v.duplicates != AllowDuplicates {
+		for _, val := range v.vals {
+			seen[v.format(val)] = true
+		}
+	}
+	parsed := make([]T, 0, len(parts))
+	for _, part := range parts {
+		val, err := v.parse(part)
+		if err != nil {
+			return err
+		}
+		if v.duplicates != AllowDuplicates {
+			key := v.format(val)
+			if seen[key] {
+				if v.duplicates == RejectDuplicates {
+					return fmt.Errorf("duplicate value %q", key)
+				}
+				continue
+			}
+			seen[key] = true
+		}
+		parsed = append(parsed, val)
+	}
+	v.vals = append(v.vals, parsed...)
+	return nil
+}
+
+func (v *sliceValue[T]) Get() any {
+	return v.vals
+}
+
+// clone returns a copy of v with its own backing array, so appending to one does not write into
+// the other. Types embedding sliceValue use it in their Clone methods.
+func (v *sliceValue[T]) clone() sliceValue[T] {
+	clone := *v
+	clone.vals = slices.Clone(v.vals)
+	return clone
+}
@@ -3,6 +3,7 @@ package flagtype
 import (
 	"flag"
 	"fmt"
+	"maps"
 	"sort"
 	"strings"
 )
@@ -38,12 +39,9 @@ func (v *stringMapValue) String() string {
 }
 
 func (v *stringMapValue) Set(s string) error {
-	key, value, ok := strings.Cut(s, "=")
-	if !ok {
-		return fmt.Errorf("invalid key=value pair: %q (missing '=')", s)
-	}
-	if key == "" {
-		return fmt.Errorf("invalid key=value pair: %q (empty key)", s)
+	key, value, err := parseKeyValue(s)
+	if err != nil {
+		return err
 	}
 	if v.m == nil {
 		v.m = make(map[string]string)
@@ -55,3 +53,19 @@ func (v *stringMapValue) Set(s string) error {
 func (v *stringMapValue) Get() any {
 	return v.m
 }
+
+// parseKeyValue splits s on the first "=" into a non-empty key and a (possibly empty) value.
+func parseKeyValue(s string) (key, value string, err error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return "", "", fmt.Errorf("invalid key=value pair: %q (missing '=')", s)
+	}
+	if key == "" {
+		return "", "", fmt.Errorf("invalid key=value pair: %q (empty key)", s)
+	}
+	return key, value, nil
+}
+
+func (v *stringMapValue) Clone() flag.Value {
+	return &stringMapValue{m: maps.Clone(v.m)}
+}
@@ -0,0 +1,68 @@
+package flagtype
+
+import (
+	"flag"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type stringMultiMapValue struct {
+	m map[string][]string
+}
+
+// StringMultiMap returns a [flag.Value] that parses key=value pairs into a map of string slices.
+// Unlike [StringMap], repeating a key appends to its values instead of overwriting them, so
+// --param=id=1 --param=id=2 yields {"id": ["1", "2"]}. This suits query-parameter and header
+// style flags. The value is split on the first "=" character.
+//
+// Use [cli.GetFlag] with type map[string][]string to retrieve the value.
+func StringMultiMap() flag.Value {
+	return &stringMultiMapValue{}
+}
+
+func (v *stringMultiMapValue) String() string {
+	if v.m == nil {
+		return ""
+	}
+	// Sort keys for deterministic output; values keep the order they were given in.
+	keys := make([]string, 0, len(v.m))
+	for k := range v.m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, k := range keys {
+		for _, val := range v.m[k] {
+			pairs = append(pairs, k+"="+val)
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v *stringMultiMapValue) Set(s string) error {
+	key, value, err := parseKeyValue(s)
+	if err != nil {
+		return err
+	}
+	if v.m == nil {
+		v.m = make(map[string][]string)
+	}
+	v.m[key] = append(v.m[key], value)
+	return nil
+}
+
+func (v *stringMultiMapValue) Get() any {
+	return v.m
+}
+
+func (v *stringMultiMapValue) Clone() flag.Value {
+	clone := &stringMultiMapValue{}
+	if v.m != nil {
+		clone.m = make(map[string][]string, len(v.m))
+		for k, vals := range v.m {
+			clone.m[k] = slices.Clone(vals)
+		}
+	}
+	return clone
+}
@@ -1,31 +1,37 @@
 package flagtype
 
-import (
-	"flag"
-	"strings"
-)
+import "flag"
 
 type stringSliceValue struct {
-	vals []string
+	sliceValue[string]
 }
 
 // StringSlice returns a [flag.Value] that collects values into a string slice. Each time the flag
-// is set, the value is appended. This allows repeatable flags like --tag=foo --tag=bar.
+// is set, the value is appended. This allows repeatable flags like --tag=foo --tag=bar. Use
+// [WithSeparator] (or [StringSliceDelim]) to also split each occurrence, like --tag=foo,bar.
 //
 // Use [cli.GetFlag] with type []string to retrieve the value.
-func StringSlice() flag.Value {
-	return &stringSliceValue{}
+func StringSlice(opts ...SliceOption) flag.Value {
+	return &stringSliceValue{newSliceValue(parseString, identity, opts)}
 }
 
-func (v *stringSliceValue) String() string {
-	return strings.Join(v.vals, ",")
+// StringSliceDelim is like [StringSlice] but splits each occurrence on sep, so --tags=a,b,c adds
+// three values. As with [WithSeparator], whitespace around each value is trimmed and empty values
+// are skipped. The flag can still be repeated.
+//
+// Use [cli.GetFlag] with type []string to retrieve the value.
+func StringSliceDelim(sep string, opts ...SliceOption) flag.Value {
+	return StringSlice(append([]SliceOption{WithSeparator(sep)}, opts...)...)
+}
+
+func parseString(s string) (string, error) {
+	return s, nil
 }
 
-func (v *stringSliceValue) Set(s string) error {
-	v.vals = append(v.vals, s)
-	return nil
+func identity(s string) string {
+	return s
 }
 
-func (v *stringSliceValue) Get() any {
-	return v.vals
+func (v *stringSliceValue) Clone() flag.Value {
+	return &stringSliceValue{v.sliceValue.clone()}
 }
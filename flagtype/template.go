@@ -0,0 +1,47 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+	"text/template"
+)
+
+type templateValue struct {
+	raw   string
+	tmpl  *template.Template
+	funcs template.FuncMap
+}
+
+// Template returns a [flag.Value] that compiles the flag value as a [text/template] at parse time,
+// so output format flags like --format '{{.Name}}' report syntax errors before the command runs.
+//
+// Use [cli.GetFlag] with type *template.Template to retrieve the value. It is nil if the flag was
+// not set.
+func Template() flag.Value {
+	return &templateValue{}
+}
+
+// TemplateWithFuncs is like [Template] but makes funcs available to the template, which is
+// required for the template to parse if it calls them.
+//
+// Use [cli.GetFlag] with type *template.Template to retrieve the value.
+func TemplateWithFuncs(funcs template.FuncMap) flag.Value {
+	return &templateValue{funcs: funcs}
+}
+
+func (v *templateValue) String() string {
+	return v.raw
+}
+
+func (v *templateValue) Set(s string) error {
+	tmpl, err := template.New("flag").Funcs(v.funcs).Parse(s)
+	if err != nil {
+		return fmt.Errorf("invalid template %q: %w", s, err)
+	}
+	v.raw, v.tmpl = s, tmpl
+	return nil
+}
+
+func (v *templateValue) Get() any {
+	return v.tmpl
+}
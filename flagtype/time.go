@@ -0,0 +1,61 @@
+package flagtype
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type timeValue struct {
+	t       time.Time
+	layouts []string
+}
+
+// Time returns a [flag.Value] that parses a timestamp. The value is tried against each layout in
+// order, defaulting to [time.RFC3339] when no layouts are given. Times relative to the current time
+// are also accepted: "now", or "now" followed by a signed duration, like "now-24h" or "now+30m".
+//
+// Use [cli.GetFlag] with type time.Time to retrieve the value.
+func Time(layouts ...string) flag.Value {
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339}
+	}
+	return &timeValue{layouts: layouts}
+}
+
+func (v *timeValue) String() string {
+	if v.t.IsZero() {
+		return ""
+	}
+	return v.t.Format(v.layouts[0])
+}
+
+func (v *timeValue) Set(s string) error {
+	if rest, ok := strings.CutPrefix(s, "now"); ok {
+		var offset time.Duration
+		if rest != "" {
+			if rest[0] != '+' && rest[0] != '-' {
+				return fmt.Errorf("invalid time %q: expected now, now+<duration>, or now-<duration>", s)
+			}
+			d, err := time.ParseDuration(rest)
+			if err != nil {
+				return fmt.Errorf("invalid time %q: %w", s, err)
+			}
+			offset = d
+		}
+		v.t = time.Now().Add(offset)
+		return nil
+	}
+	for _, layout := range v.layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			v.t = t
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid time %q, must match layout: %s", s, strings.Join(v.layouts, ", "))
+}
+
+func (v *timeValue) Get() any {
+	return v.t
+}
@@ -0,0 +1,45 @@
+package flagtype
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+)
+
+type timezoneValue struct {
+	loc *time.Location
+}
+
+// Timezone returns a [flag.Value] that loads a time zone with [time.LoadLocation], like
+// America/New_York, UTC, or Local. Unknown names are rejected at parse time. Loading named zones
+// requires the IANA time zone database on the system, or importing time/tzdata in the program.
+//
+// Use [cli.GetFlag] with type *time.Location to retrieve the value. It is nil if the flag was not
+// set.
+func Timezone() flag.Value {
+	return &timezoneValue{}
+}
+
+func (v *timezoneValue) String() string {
+	if v.loc == nil {
+		return ""
+	}
+	return v.loc.String()
+}
+
+func (v *timezoneValue) Set(s string) error {
+	if s == "" {
+		return errors.New("time zone must not be empty")
+	}
+	loc, err := time.LoadLocation(s)
+	if err != nil {
+		return fmt.Errorf("invalid time zone %q", s)
+	}
+	v.loc = loc
+	return nil
+}
+
+func (v *timezoneValue) Get() any {
+	return v.loc
+}
@@ -4,10 +4,13 @@ import (
 	"flag"
 	"fmt"
 	"net/url"
+	"slices"
+	"strings"
 )
 
 type urlValue struct {
-	u *url.URL
+	u       *url.URL
+	schemes []string
 }
 
 // URL returns a [flag.Value] that parses the flag value as a URL. The URL must have both a scheme
@@ -18,6 +21,14 @@ func URL() flag.Value {
 	return &urlValue{}
 }
 
+// URLScheme is like [URL] but only accepts the given schemes, like URLScheme("https", "postgres").
+// Schemes are matched case-insensitively.
+//
+// Use [cli.GetFlag] with type *url.URL to retrieve the value.
+func URLScheme(schemes ...string) flag.Value {
+	return &urlValue{schemes: schemes}
+}
+
 func (v *urlValue) String() string {
 	if v.u == nil {
 		return ""
@@ -26,12 +37,9 @@ func (v *urlValue) String() string {
 }
 
 func (v *urlValue) Set(s string) error {
-	u, err := url.Parse(s)
+	u, err := parseURL(s, v.schemes)
 	if err != nil {
-		return fmt.Errorf("invalid URL %q: %w", s, err)
-	}
-	if u.Scheme == "" || u.Host == "" {
-		return fmt.Errorf("invalid URL %q: must have a scheme and host", s)
+		return err
 	}
 	v.u = u
 	return nil
@@ -40,3 +48,40 @@ func (v *urlValue) Set(s string) error {
 func (v *urlValue) Get() any {
 	return v.u
 }
+
+type urlSliceValue struct {
+	sliceValue[*url.URL]
+}
+
+// URLSlice returns a [flag.Value] that collects URLs into a slice, like --endpoint=https://a.example
+// --endpoint=https://b.example. Each URL must have both a scheme and a host.
+//
+// Use [cli.GetFlag] with type []*url.URL to retrieve the value.
+func URLSlice(opts ...SliceOption) flag.Value {
+	parse := func(s string) (*url.URL, error) {
+		return parseURL(s, nil)
+	}
+	return &urlSliceValue{newSliceValue(parse, (*url.URL).String, opts)}
+}
+
+// parseURL parses s as a URL with a scheme and host. If schemes is non-empty, the scheme must be
+// one of them.
+func parseURL(s string, schemes []string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", s, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q: must have a scheme and host", s)
+	}
+	if len(schemes) > 0 && !slices.ContainsFunc(schemes, func(scheme string) bool {
+		return strings.EqualFold(scheme, u.Scheme)
+	}) {
+		return nil, fmt.Errorf("invalid URL %q: scheme must be one of: %s", s, strings.Join(schemes, ", "))
+	}
+	return u, nil
+}
+
+func (v *urlSliceValue) Clone() flag.Value {
+	return &urlSliceValue{v.sliceValue.clone()}
+}
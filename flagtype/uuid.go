@@ -0,0 +1,60 @@
+package flagtype
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+type uuidValue struct {
+	id string
+}
+
+// UUID returns a [flag.Value] that validates a UUID, written either in canonical form
+// (123e4567-e89b-12d3-a456-426614174000) or as 32 hex digits without dashes. The value is
+// normalized to the lowercase canonical form.
+//
+// Use [cli.GetFlag] with type string to retrieve the value.
+func UUID() flag.Value {
+	return &uuidValue{}
+}
+
+func (v *uuidValue) String() string {
+	return v.id
+}
+
+func (v *uuidValue) Set(s string) error {
+	id, ok := normalizeUUID(s)
+	if !ok {
+		return fmt.Errorf("invalid UUID %q", s)
+	}
+	v.id = id
+	return nil
+}
+
+func (v *uuidValue) Get() any {
+	return v.id
+}
+
+// normalizeUUID returns the lowercase canonical form of s, reporting false if s is not a UUID.
+func normalizeUUID(s string) (string, bool) {
+	var digits string
+	switch len(s) {
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return "", false
+		}
+		digits = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	case 32:
+		digits = s
+	default:
+		return "", false
+	}
+	var b [16]byte
+	if _, err := hex.Decode(b[:], []byte(digits)); err != nil {
+		return "", false
+	}
+	h := strings.ToLower(digits)
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], true
+}
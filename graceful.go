@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/pressly/cli/graceful"
+)
+
+// GracefulExec wraps an Exec function with the shutdown lifecycle of the graceful package, for
+// long-running commands such as servers and workers:
+//
+//	Exec: cli.GracefulExec(func(ctx context.Context, s *cli.State) error {
+//	    return serve(ctx, s)
+//	}, graceful.WithTerminationTimeout(30*time.Second)),
+//
+// The first SIGINT or SIGTERM (or cancellation of the context passed to [Run]) cancels the context
+// passed to exec, a second signal forces the command to return, and graceful options like
+// termination and run timeouts apply as they do for [graceful.Run]. Shutdown messages are written
+// to [State].Stderr unless the options configure a logger or another writer.
+//
+// Unlike [graceful.Run], the process is never exited. A forced or timed-out shutdown returns
+// [graceful.ErrForcedShutdown], [graceful.ErrImmediateTermination], or
+// [graceful.ErrShutdownTimeout], which report exit codes 130 and 124 through an ExitCode method,
+// as seen by [RunOptions].OnCommandComplete.
+func GracefulExec(exec func(ctx context.Context, s *State) error, opts ...graceful.Option) func(ctx context.Context, s *State) error {
+	return func(ctx context.Context, s *State) error {
+		opts := append([]graceful.Option{graceful.WithStderr(s.Stderr)}, opts...)
+		return graceful.RunContext(ctx, func(ctx context.Context) error {
+			return exec(ctx, s)
+		}, opts...)
+	}
+}
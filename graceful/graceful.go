@@ -6,11 +6,15 @@
 // process an opportunity to shut down cleanly. A second signal forces an immediate exit. Optional
 // timeouts bound both the maximum run duration (WithRunTimeout) and the total shutdown period
 // (WithTerminationTimeout). For scenarios requiring immediate termination on the first signal, use
-// WithImmediateTermination to bypass the graceful shutdown phase.
+// WithImmediateTermination to bypass the graceful shutdown phase. Use WithSignals to change which
+// signals trigger shutdown.
+//
+// Run exits the process when done. RunContext runs the same lifecycle but returns an error instead,
+// which ExitCode maps to an exit code.
 //
 // Exit codes:
 //   - 0: successful completion
-//   - 1: run function returned an error
+//   - 1: run function or a shutdown hook returned an error
 //   - 124: shutdown timeout exceeded
 //   - 130: forced shutdown (second signal or immediate termination)
 //
@@ -50,13 +54,16 @@ package graceful
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"runtime"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
@@ -67,18 +74,127 @@ var osExit = os.Exit
 
 func exit(code int) { osExit(code) }
 
+// Errors returned by [RunContext] when the run function did not finish on its own. Each has an
+// ExitCode method reporting its exit code; use [ExitCode] to map them to the exit codes used by
+// [Run].
+var (
+	// ErrForcedShutdown is returned when a second signal arrives during graceful shutdown.
+	ErrForcedShutdown error = &exitError{msg: "forced shutdown", code: 130}
+	// ErrImmediateTermination is returned on the first signal when WithImmediateTermination is set.
+	ErrImmediateTermination error = &exitError{msg: "immediate termination", code: 130}
+	// ErrShutdownTimeout is returned when the termination timeout expires during shutdown.
+	ErrShutdownTimeout error = &exitError{msg: "shutdown timeout exceeded", code: 124}
+)
+
+type exitError struct {
+	msg  string
+	code int
+}
+
+func (e *exitError) Error() string { return e.msg }
+
+// ExitCode returns the exit code used by Run for this error.
+func (e *exitError) ExitCode() int { return e.code }
+
+// ExitCode returns the process exit code for an error returned by [RunContext]: 0 for nil, 124 for
+// [ErrShutdownTimeout], 130 for [ErrForcedShutdown] and [ErrImmediateTermination], and 1 for any
+// other error. An error that has an ExitCode() int method, anywhere in its chain, reports its own
+// code.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return 1
+}
+
 // Run the provided function with signal handling and optional timeouts. See package documentation
 // for details on signal handling, timeouts, and exit codes.
+//
+// Run exits the process when done, unless an exit function is set with WithExitFunc. Use
+// [RunContext] to get the result as an error instead.
 func Run(run func(context.Context) error, opts ...Option) {
-	cfg := config{
-		stderr: os.Stderr,
+	cfg := newConfig(opts)
+	err := runContext(context.Background(), run, cfg)
+	reason := ExitCompleted
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrShutdownTimeout):
+		reason = ExitTimeout
+		cfg.log(slog.LevelError, err.Error())
+	case errors.Is(err, ErrForcedShutdown), errors.Is(err, ErrImmediateTermination):
+		reason = ExitForced
+		cfg.log(slog.LevelWarn, err.Error())
+	default:
+		if cfg.logger != nil {
+			cfg.logger.Error("function error", slog.Any("error", err))
+		} else {
+			_, _ = fmt.Fprintln(cfg.stderr, err)
+		}
 	}
-	for _, opt := range opts {
-		opt(&cfg)
+	if cfg.exitFunc != nil {
+		cfg.exitFunc(ExitCode(err), reason)
+		return
 	}
+	exit(ExitCode(err))
+}
+
+// ExitReason describes why Run is exiting. See WithExitFunc.
+type ExitReason int
+
+const (
+	// ExitCompleted means the run function returned, successfully or with an error.
+	ExitCompleted ExitReason = iota
+	// ExitForced means a second signal arrived, or the first with WithImmediateTermination.
+	ExitForced
+	// ExitTimeout means the termination timeout expired before the run function returned.
+	ExitTimeout
+)
 
-	// Main cancellation context (first signal)
-	ctx, stop := signal.NotifyContext(context.Background(), interrupt()...)
+func (r ExitReason) String() string {
+	switch r {
+	case ExitCompleted:
+		return "completed"
+	case ExitForced:
+		return "forced"
+	case ExitTimeout:
+		return "timeout"
+	}
+	return "ExitReason(" + strconv.Itoa(int(r)) + ")"
+}
+
+// RunContext is like [Run] but returns instead of exiting the process, so the graceful lifecycle
+// can be embedded in a larger program, such as a cli command's Exec function, and tested directly.
+// Canceling ctx has the same effect as the first signal.
+//
+// RunContext returns nil if the run function completed successfully, and the error (joined with
+// any shutdown hook errors) if it failed. If a second signal arrives or the termination timeout
+// expires, it returns [ErrForcedShutdown], [ErrImmediateTermination], or [ErrShutdownTimeout]
+// without waiting for the run function, which may still be running. Use [ExitCode] to map the
+// result to an exit code. Unlike Run, RunContext does not report the returned error.
+func RunContext(ctx context.Context, run func(context.Context) error, opts ...Option) error {
+	return runContext(ctx, run, newConfig(opts))
+}
+
+func runContext(parent context.Context, run func(context.Context) error, cfg config) error {
+	signals := interrupt()
+	if cfg.signalsSet {
+		signals = cfg.signals
+	}
+
+	// Main cancellation context (first signal), unless the caller manages it.
+	var (
+		ctx  context.Context
+		stop context.CancelFunc
+	)
+	if cfg.notifyCtx != nil {
+		ctx, stop = context.WithCancel(cfg.notifyCtx)
+	} else {
+		ctx, stop = notifyContext(parent, signals)
+	}
 	defer stop()
 
 	// Apply run timeout if configured
@@ -96,40 +212,23 @@ func Run(run func(context.Context) error, opts ...Option) {
 	select {
 	case err := <-done:
 		// fn completed before any signal
-		if err != nil {
-			if cfg.logger != nil {
-				cfg.logger.Error("function error", slog.Any("error", err))
-			} else {
-				_, _ = fmt.Fprintln(cfg.stderr, err)
-			}
-			exit(1)
-		}
-		exit(0)
+		return err
 
 	case <-ctx.Done():
 		// Check if immediate termination is requested
 		if cfg.immediateTermination {
-			msg := "immediate termination"
-			if cfg.logger != nil {
-				cfg.logger.Warn(msg)
-			} else {
-				_, _ = fmt.Fprintln(cfg.stderr, msg)
-			}
-			exit(130)
+			return ErrImmediateTermination
 		}
 
 		// First signal received - NOW set up second signal detector
 		second := make(chan os.Signal, 1)
-		signal.Notify(second, interrupt()...)
-		defer signal.Stop(second)
-
-		msg := "shutting down gracefully (press ctrl+c again to force quit)"
-		if cfg.logger != nil {
-			cfg.logger.Info(msg)
-		} else {
-			_, _ = fmt.Fprintln(cfg.stderr, msg)
+		if len(signals) > 0 {
+			signal.Notify(second, signals...)
+			defer signal.Stop(second)
 		}
 
+		cfg.log(slog.LevelInfo, "shutting down gracefully (press ctrl+c again to force quit)")
+
 		// Set up shutdown timeout if configured
 		var timeoutChan <-chan time.Time
 		if cfg.shutdownTimeout > 0 {
@@ -138,38 +237,31 @@ func Run(run func(context.Context) error, opts ...Option) {
 			timeoutChan = timer.C
 		}
 
-		select {
-		case err := <-done:
-			// fn completed during graceful shutdown
-			if err != nil {
-				if cfg.logger != nil {
-					cfg.logger.Error("function error", "error", err)
-				} else {
-					_, _ = fmt.Fprintln(cfg.stderr, err)
-				}
-				exit(1)
+		// Run shutdown hooks alongside the draining run function. Both must finish before the
+		// process exits, and either can still be cut short by a second signal or the timeout.
+		finished := done
+		if len(cfg.onShutdown) > 0 {
+			hookCtx := context.Background()
+			if cfg.shutdownTimeout > 0 {
+				var cancel context.CancelFunc
+				hookCtx, cancel = context.WithTimeout(hookCtx, cfg.shutdownTimeout)
+				defer cancel()
 			}
-			exit(0)
+			finished = make(chan error, 1)
+			go func() {
+				hookErr := cfg.runShutdownHooks(hookCtx)
+				finished <- errors.Join(<-done, hookErr)
+			}()
+		}
 
+		select {
+		case err := <-finished:
+			// fn (and any shutdown hooks) completed during graceful shutdown
+			return err
 		case <-second:
-			// Second signal received
-			msg := "forced shutdown"
-			if cfg.logger != nil {
-				cfg.logger.Warn(msg)
-			} else {
-				_, _ = fmt.Fprintln(cfg.stderr, msg)
-			}
-			exit(130)
-
+			return ErrForcedShutdown
 		case <-timeoutChan:
-			// Shutdown timeout expired
-			msg := "shutdown timeout exceeded"
-			if cfg.logger != nil {
-				cfg.logger.Error(msg)
-			} else {
-				_, _ = fmt.Fprintln(cfg.stderr, msg)
-			}
-			exit(124)
+			return ErrShutdownTimeout
 		}
 	}
 }
@@ -203,44 +295,159 @@ func Run(run func(context.Context) error, opts ...Option) {
 //	    graceful.WithTerminationTimeout(25*time.Second), // total shutdown limit
 //	)
 func ListenAndServe(srv *http.Server, shutdownGrace time.Duration) func(context.Context) error {
-	return func(ctx context.Context) error {
-		var wg sync.WaitGroup
-		serverErr := make(chan error, 1)
+	return Serve(func() error {
+		var err error
+		if srv.TLSConfig != nil {
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("listen: %w", err)
+		}
+		return nil
+	}, srv.Shutdown, shutdownGrace)
+}
 
-		// Run the HTTP/HTTPS server
-		wg.Add(1)
+// Serve adapts any server with blocking start and graceful stop functions to the lifecycle managed
+// by graceful.Run, so non-HTTP daemons get the same drain semantics as ListenAndServe. It calls
+// start in a goroutine and waits for ctx cancellation, then calls stop with a context that expires
+// after shutdownGrace and waits for start to return.
+//
+// If start returns before ctx is canceled, its error is returned without calling stop. Once stop
+// has been called, the error from start is ignored, since servers typically report being closed
+// as an error; the error from stop is returned instead.
+//
+// Example:
+//
+//	graceful.Run(graceful.Serve(
+//	    func() error { return broker.Run() },
+//	    broker.Drain,
+//	    10*time.Second,
+//	))
+func Serve(start func() error, stop func(context.Context) error, shutdownGrace time.Duration) func(context.Context) error {
+	return func(ctx context.Context) error {
+		startErr := make(chan error, 1)
 		go func() {
-			defer wg.Done()
-			var err error
-			if srv.TLSConfig != nil {
-				err = srv.ListenAndServeTLS("", "")
-			} else {
-				err = srv.ListenAndServe()
-			}
-			if err != nil && err != http.ErrServerClosed {
-				serverErr <- fmt.Errorf("listen: %w", err)
-			}
+			startErr <- start()
 		}()
 
-		// Wait for context cancellation or server error
+		// Wait for context cancellation or the server stopping on its own
 		select {
-		case err := <-serverErr:
-			wg.Wait()
+		case err := <-startErr:
 			return err
 		case <-ctx.Done():
 			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
 			defer cancel()
 
-			// Shutdown the server
-			if err := srv.Shutdown(shutdownCtx); err != nil {
-				wg.Wait()
-				return err
-			}
-
+			err := stop(shutdownCtx)
 			// Wait for the server goroutine to finish
-			wg.Wait()
+			<-startErr
+			return err
+		}
+	}
+}
+
+// ListenerServer is a server that serves connections from a net.Listener and shuts down
+// gracefully, such as *http.Server.
+type ListenerServer interface {
+	Serve(net.Listener) error
+	Shutdown(context.Context) error
+}
+
+// ServeListener is like ListenAndServe but serves on an existing listener, for servers created
+// with a custom net.Listener (for example, one from systemd socket activation) or any type that
+// implements ListenerServer. Closed-server errors from Serve, like http.ErrServerClosed, are not
+// reported.
+func ServeListener(srv ListenerServer, lis net.Listener, shutdownGrace time.Duration) func(context.Context) error {
+	return Serve(func() error {
+		err := srv.Serve(lis)
+		if errors.Is(err, http.ErrServerClosed) || errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		return err
+	}, srv.Shutdown, shutdownGrace)
+}
+
+// GRPCServer is the subset of *grpc.Server used by ServeGRPC, declared here so this package does not
+// depend on gRPC.
+type GRPCServer interface {
+	Serve(net.Listener) error
+	GracefulStop()
+	Stop()
+}
+
+// ServeGRPC runs a gRPC server on lis under the lifecycle managed by graceful.Run. On shutdown it
+// calls GracefulStop, which stops accepting new connections and waits for pending RPCs to finish.
+// If that takes longer than shutdownGrace, Stop is called to close all connections and cancel
+// in-flight RPCs, and the context error is returned.
+//
+// Example:
+//
+//	lis, err := net.Listen("tcp", ":9090")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	graceful.Run(graceful.ServeGRPC(grpcServer, lis, 15*time.Second))
+func ServeGRPC(srv GRPCServer, lis net.Listener, shutdownGrace time.Duration) func(context.Context) error {
+	return Serve(func() error {
+		return srv.Serve(lis)
+	}, func(ctx context.Context) error {
+		stopped := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
 			return nil
+		case <-ctx.Done():
+			srv.Stop()
+			<-stopped
+			return ctx.Err()
 		}
+	}, shutdownGrace)
+}
+
+// Group combines several run functions, such as an HTTP server, a metrics server, and a background
+// worker, into one that runs them concurrently under a single graceful.Run lifecycle. The first
+// function to return an error cancels the context of all the others, just as a shutdown signal
+// does. The combined function waits for every function to return and reports the first error, so
+// graceful.Run applies the termination timeout and second-signal handling to the group as a whole.
+//
+// A function that returns nil does not stop the others.
+//
+// Example:
+//
+//	graceful.Run(graceful.Group(
+//	    graceful.ListenAndServe(apiServer, 15*time.Second),
+//	    graceful.ListenAndServe(metricsServer, 5*time.Second),
+//	    worker.Run,
+//	), graceful.WithTerminationTimeout(30*time.Second))
+func Group(fns ...func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var (
+			wg       sync.WaitGroup
+			once     sync.Once
+			firstErr error
+		)
+		for _, fn := range fns {
+			wg.Add(1)
+			go func(fn func(context.Context) error) {
+				defer wg.Done()
+				if err := fn(ctx); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}(fn)
+		}
+		wg.Wait()
+		return firstErr
 	}
 }
 
@@ -253,6 +460,42 @@ type config struct {
 	runTimeout           time.Duration
 	shutdownTimeout      time.Duration
 	immediateTermination bool
+	signals              []os.Signal
+	signalsSet           bool
+	notifyCtx            context.Context
+	onShutdown           []func(context.Context) error
+	exitFunc             func(code int, reason ExitReason)
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		stderr: os.Stderr,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// log writes msg to the configured logger at the given level, or to stderr if there is no logger.
+func (c *config) log(level slog.Level, msg string) {
+	if c.logger != nil {
+		c.logger.Log(context.Background(), level, msg)
+		return
+	}
+	_, _ = fmt.Fprintln(c.stderr, msg)
+}
+
+// runShutdownHooks runs the hooks registered with WithOnShutdown in reverse registration order and
+// returns their errors joined together.
+func (c *config) runShutdownHooks(ctx context.Context) error {
+	var errs []error
+	for i := len(c.onShutdown) - 1; i >= 0; i-- {
+		if err := c.onShutdown[i](ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook: %w", err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // WithStderr sets the writer for error output. Defaults to os.Stderr if not specified. If a logger
@@ -321,6 +564,84 @@ func WithImmediateTermination() Option {
 	}
 }
 
+// WithSignals sets the signals that trigger shutdown, replacing the default of SIGINT and SIGTERM
+// (os.Interrupt on Windows). The same signals are used to detect a second signal that forces
+// termination. For example, to also shut down on SIGQUIT:
+//
+//	graceful.Run(fn, graceful.WithSignals(os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT))
+//
+// Calling WithSignals with no signals disables signal handling entirely, which is useful together
+// with WithNotifyContext or WithRunTimeout.
+func WithSignals(sig ...os.Signal) Option {
+	return func(c *config) {
+		c.signals = sig
+		c.signalsSet = true
+	}
+}
+
+// WithExitFunc sets the function Run calls instead of os.Exit, with the exit code and the reason
+// for exiting. Run returns after fn returns. This lets embedders flush buffers or report the
+// outcome before exiting, and lets tests observe the result without terminating the process.
+//
+// Example:
+//
+//	graceful.Run(fn, graceful.WithExitFunc(func(code int, reason graceful.ExitReason) {
+//	    metrics.Flush()
+//	    os.Exit(code)
+//	}))
+func WithExitFunc(fn func(code int, reason ExitReason)) Option {
+	return func(c *config) {
+		c.exitFunc = fn
+	}
+}
+
+// WithNotifyContext uses ctx to trigger shutdown instead of listening for the first signal, for
+// callers that already manage a signal context, such as one created with [signal.NotifyContext].
+// When ctx is canceled, the context passed to the run function is canceled and the graceful
+// shutdown phase begins. A second signal (see WithSignals) still forces termination.
+//
+// Example:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+//	defer stop()
+//	graceful.Run(fn, graceful.WithNotifyContext(ctx))
+func WithNotifyContext(ctx context.Context) Option {
+	return func(c *config) {
+		c.notifyCtx = ctx
+	}
+}
+
+// WithOnShutdown registers a hook that runs once the graceful shutdown phase begins (on the first
+// signal, or when the run timeout expires), concurrently with the run function as it drains. Use it
+// to flush metrics, close pools, or deregister from service discovery. The option may be given
+// more than once; hooks run one at a time in reverse registration order, like deferred calls, so
+// resources are released in the opposite order they were set up.
+//
+// The context passed to hooks is canceled when the termination timeout expires. Errors from hooks
+// are reported like an error from the run function, resulting in exit code 1. Hooks do not run on
+// normal completion or with WithImmediateTermination.
+//
+// Example:
+//
+//	graceful.Run(fn,
+//	    graceful.WithOnShutdown(func(ctx context.Context) error { return db.Close() }),
+//	    graceful.WithOnShutdown(registry.Deregister),
+//	)
+func WithOnShutdown(hook func(ctx context.Context) error) Option {
+	return func(c *config) {
+		c.onShutdown = append(c.onShutdown, hook)
+	}
+}
+
+// notifyContext is like signal.NotifyContext, except that an empty list of signals means no
+// signals rather than all of them.
+func notifyContext(parent context.Context, signals []os.Signal) (context.Context, context.CancelFunc) {
+	if len(signals) == 0 {
+		return context.WithCancel(parent)
+	}
+	return signal.NotifyContext(parent, signals...)
+}
+
 // interrupt returns the list of signals to listen for interrupt events. On Unix-like systems, this
 // includes SIGINT and SIGTERM. On Windows, only os.interrupt is included.
 func interrupt() []os.Signal {
@@ -1,10 +1,15 @@
 package graceful
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"runtime"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
@@ -189,3 +194,443 @@ func TestRun_ImmediateTermination(t *testing.T) {
 		t.Fatalf("expected immediate termination exit 130, got %d", code)
 	}
 }
+
+func TestRun_WithSignals(t *testing.T) {
+	started := make(chan struct{})
+
+	code := captureExitCode(t, func() {
+		go func() {
+			<-started
+			_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+		}()
+
+		Run(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return nil
+		}, WithSignals(syscall.SIGUSR1))
+	})
+
+	if code != 0 {
+		t.Fatalf("expected exit 0 after custom signal, got %d", code)
+	}
+}
+
+func TestRun_WithNotifyContext(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+
+	code := captureExitCode(t, func() {
+		go func() {
+			<-started
+			cancel()
+		}()
+
+		Run(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return nil
+		}, WithNotifyContext(parent), WithTerminationTimeout(time.Second))
+	})
+
+	if code != 0 {
+		t.Fatalf("expected exit 0 after notify context canceled, got %d", code)
+	}
+}
+
+func TestRun_WithoutSignals(t *testing.T) {
+	code := captureExitCode(t, func() {
+		Run(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, WithSignals(), WithRunTimeout(10*time.Millisecond))
+	})
+
+	if code != 1 {
+		t.Fatalf("expected exit 1 after run timeout, got %d", code)
+	}
+}
+
+func TestRun_OnShutdownHooks(t *testing.T) {
+	started := make(chan struct{})
+	var order []string
+
+	code := captureExitCode(t, func() {
+		go sendSignal(started, 0)
+
+		Run(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return nil
+		},
+			WithOnShutdown(func(ctx context.Context) error {
+				order = append(order, "first")
+				return nil
+			}),
+			WithOnShutdown(func(ctx context.Context) error {
+				order = append(order, "second")
+				return nil
+			}),
+			WithTerminationTimeout(time.Second),
+		)
+	})
+
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	if len(order) != 2 || order[0] != "second" || order[1] != "first" {
+		t.Fatalf("expected hooks to run in reverse order, got %v", order)
+	}
+}
+
+func TestRun_OnShutdownHookError(t *testing.T) {
+	started := make(chan struct{})
+
+	code := captureExitCode(t, func() {
+		go sendSignal(started, 0)
+
+		Run(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return nil
+		}, WithOnShutdown(func(ctx context.Context) error {
+			return errors.New("deregister failed")
+		}))
+	})
+
+	if code != 1 {
+		t.Fatalf("expected exit 1 after hook error, got %d", code)
+	}
+}
+
+func TestRun_OnShutdownNotRunOnCompletion(t *testing.T) {
+	ran := false
+	code := captureExitCode(t, func() {
+		Run(func(ctx context.Context) error { return nil }, WithOnShutdown(func(ctx context.Context) error {
+			ran = true
+			return nil
+		}))
+	})
+
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	if ran {
+		t.Fatal("expected shutdown hook not to run on normal completion")
+	}
+}
+
+func TestGroup_FirstErrorCancelsOthers(t *testing.T) {
+	boom := errors.New("boom")
+	canceled := make(chan struct{})
+
+	err := Group(
+		func(ctx context.Context) error {
+			<-ctx.Done()
+			close(canceled)
+			return nil
+		},
+		func(ctx context.Context) error { return boom },
+	)(context.Background())
+
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+	select {
+	case <-canceled:
+	default:
+		t.Fatal("expected other function to be canceled")
+	}
+}
+
+func TestGroup_WaitsForAll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var stopped [3]bool
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Group(
+			func(ctx context.Context) error { <-ctx.Done(); stopped[0] = true; return nil },
+			func(ctx context.Context) error { <-ctx.Done(); stopped[1] = true; return nil },
+			func(ctx context.Context) error { stopped[2] = true; return nil },
+		)(ctx)
+	}()
+	cancel()
+
+	if err := <-errCh; err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if stopped != [3]bool{true, true, true} {
+		t.Fatalf("expected all functions to stop, got %v", stopped)
+	}
+}
+
+func TestRun_Group(t *testing.T) {
+	code := captureExitCode(t, func() {
+		Run(Group(
+			func(ctx context.Context) error { <-ctx.Done(); return nil },
+			func(ctx context.Context) error { return errors.New("worker failed") },
+		), WithTerminationTimeout(time.Second))
+	})
+
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d", code)
+	}
+}
+
+func TestRunContext(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		err := RunContext(context.Background(), func(ctx context.Context) error { return nil })
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		boom := errors.New("boom")
+		err := RunContext(context.Background(), func(ctx context.Context) error { return boom })
+		if !errors.Is(err, boom) {
+			t.Fatalf("expected boom, got %v", err)
+		}
+		if code := ExitCode(err); code != 1 {
+			t.Fatalf("expected exit code 1, got %d", code)
+		}
+	})
+	t.Run("parent cancellation starts shutdown", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var stderr bytes.Buffer
+		err := RunContext(ctx, func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}, WithStderr(&stderr))
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if !strings.Contains(stderr.String(), "shutting down gracefully") {
+			t.Fatalf("expected shutdown message, got %q", stderr.String())
+		}
+	})
+	t.Run("shutdown timeout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := RunContext(ctx, func(ctx context.Context) error {
+			select {}
+		}, WithTerminationTimeout(10*time.Millisecond), WithStderr(io.Discard))
+		if !errors.Is(err, ErrShutdownTimeout) {
+			t.Fatalf("expected ErrShutdownTimeout, got %v", err)
+		}
+		if code := ExitCode(err); code != 124 {
+			t.Fatalf("expected exit code 124, got %d", code)
+		}
+	})
+	t.Run("immediate termination", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := RunContext(ctx, func(ctx context.Context) error {
+			select {}
+		}, WithImmediateTermination())
+		if code := ExitCode(err); code != 130 {
+			t.Fatalf("expected exit code 130, got %d (%v)", code, err)
+		}
+	})
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{errors.New("boom"), 1},
+		{ErrShutdownTimeout, 124},
+		{ErrForcedShutdown, 130},
+		{fmt.Errorf("wrapped: %w", ErrImmediateTermination), 130},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	t.Run("stop on cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stopCh := make(chan struct{})
+		var gotDeadline bool
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- Serve(func() error {
+				<-stopCh
+				return errors.New("server closed")
+			}, func(ctx context.Context) error {
+				_, gotDeadline = ctx.Deadline()
+				close(stopCh)
+				return nil
+			}, time.Second)(ctx)
+		}()
+		cancel()
+
+		if err := <-errCh; err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if !gotDeadline {
+			t.Fatal("expected stop context to have a deadline")
+		}
+	})
+	t.Run("start error", func(t *testing.T) {
+		boom := errors.New("address in use")
+		err := Serve(func() error { return boom }, func(ctx context.Context) error {
+			t.Fatal("stop should not be called")
+			return nil
+		}, time.Second)(context.Background())
+		if !errors.Is(err, boom) {
+			t.Fatalf("expected start error, got %v", err)
+		}
+	})
+}
+
+func TestServeListener(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- ServeListener(server, lis, time.Second)(ctx)
+	}()
+
+	resp, err := http.Get("http://" + lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", resp.StatusCode)
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
+
+// fakeGRPCServer mimics *grpc.Server: GracefulStop blocks until pending RPCs finish, or Stop is
+// called.
+type fakeGRPCServer struct {
+	pending chan struct{}
+	closed  chan struct{}
+	stopped bool
+}
+
+func (s *fakeGRPCServer) Serve(net.Listener) error { <-s.closed; return nil }
+func (s *fakeGRPCServer) GracefulStop() {
+	select {
+	case <-s.pending:
+	case <-s.closed:
+	}
+	s.close()
+}
+func (s *fakeGRPCServer) Stop() { s.stopped = true; s.close() }
+func (s *fakeGRPCServer) close() {
+	select {
+	case <-s.closed:
+	default:
+		close(s.closed)
+	}
+}
+
+func TestServeGRPC(t *testing.T) {
+	t.Run("graceful stop", func(t *testing.T) {
+		srv := &fakeGRPCServer{pending: make(chan struct{}), closed: make(chan struct{})}
+		close(srv.pending)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := ServeGRPC(srv, nil, time.Second)(ctx); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if srv.stopped {
+			t.Fatal("expected GracefulStop without Stop")
+		}
+	})
+	t.Run("grace period exceeded", func(t *testing.T) {
+		srv := &fakeGRPCServer{pending: make(chan struct{}), closed: make(chan struct{})}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := ServeGRPC(srv, nil, 10*time.Millisecond)(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+		if !srv.stopped {
+			t.Fatal("expected Stop after grace period")
+		}
+	})
+}
+
+func TestRun_WithExitFunc(t *testing.T) {
+	t.Run("completed", func(t *testing.T) {
+		var (
+			gotCode   = -1
+			gotReason ExitReason
+		)
+		Run(func(ctx context.Context) error {
+			return errors.New("boom")
+		}, WithStderr(io.Discard), WithExitFunc(func(code int, reason ExitReason) {
+			gotCode, gotReason = code, reason
+		}))
+		if gotCode != 1 || gotReason != ExitCompleted {
+			t.Fatalf("expected 1 completed, got %d %v", gotCode, gotReason)
+		}
+	})
+	t.Run("timeout", func(t *testing.T) {
+		var (
+			gotCode   = -1
+			gotReason ExitReason
+		)
+		Run(func(ctx context.Context) error {
+			<-ctx.Done()
+			select {}
+		},
+			WithStderr(io.Discard),
+			WithRunTimeout(10*time.Millisecond),
+			WithTerminationTimeout(10*time.Millisecond),
+			WithExitFunc(func(code int, reason ExitReason) {
+				gotCode, gotReason = code, reason
+			}),
+		)
+		if gotCode != 124 || gotReason != ExitTimeout {
+			t.Fatalf("expected 124 timeout, got %d %v", gotCode, gotReason)
+		}
+	})
+	t.Run("forced", func(t *testing.T) {
+		started := make(chan struct{})
+		go sendSignal(started, 0)
+		var (
+			gotCode   = -1
+			gotReason ExitReason
+		)
+		Run(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			select {}
+		},
+			WithStderr(io.Discard),
+			WithImmediateTermination(),
+			WithExitFunc(func(code int, reason ExitReason) {
+				gotCode, gotReason = code, reason
+			}),
+		)
+		if gotCode != 130 || gotReason != ExitForced {
+			t.Fatalf("expected 130 forced, got %d %v", gotCode, gotReason)
+		}
+		if gotReason.String() != "forced" {
+			t.Fatalf("expected reason string forced, got %q", gotReason.String())
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package graceful
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Health serves readiness and liveness probes for a process run by graceful.Run. Create one with
+// HealthServer and wrap the run function with Health.Run.
+type Health struct {
+	addr     string
+	draining atomic.Bool
+	running  atomic.Bool
+}
+
+// HealthServer returns a Health that serves probes on addr, like ":8081":
+//
+//   - /readyz responds 200 "ready" while the run function is active, and 503 "draining" once
+//     shutdown has begun (or before the run function starts)
+//   - /livez responds 200 "ok" for as long as the health server is up
+//
+// The health server keeps serving while the run function drains, so load balancers and
+// orchestrators stop routing traffic to the process before it exits, and is shut down after the
+// run function returns.
+//
+// Example:
+//
+//	health := graceful.HealthServer(":8081")
+//	graceful.Run(
+//	    health.Run(graceful.ListenAndServe(server, 15*time.Second)),
+//	    graceful.WithTerminationTimeout(30*time.Second),
+//	)
+func HealthServer(addr string) *Health {
+	return &Health{addr: addr}
+}
+
+// Ready reports whether the run function is active and shutdown has not begun.
+func (h *Health) Ready() bool {
+	return h.running.Load() && !h.draining.Load()
+}
+
+// Handler returns the probe handler, for serving /readyz and /livez on an existing server instead
+// of the address given to HealthServer.
+func (h *Health) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !h.Ready() {
+			http.Error(w, "draining", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ready\n"))
+	})
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	return mux
+}
+
+// Run wraps run so the health server is listening before run starts, reports draining as soon as
+// ctx is canceled, and stops after run returns. An error from listening on the health address is
+// returned without calling run.
+func (h *Health) Run(run func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		lis, err := net.Listen("tcp", h.addr)
+		if err != nil {
+			return err
+		}
+		srv := &http.Server{Handler: h.Handler(), ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			_ = srv.Serve(lis)
+		}()
+		defer func() {
+			// Probes are short-lived, so there's little to drain.
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			_ = srv.Shutdown(shutdownCtx)
+		}()
+
+		h.running.Store(true)
+		defer h.running.Store(false)
+		stop := context.AfterFunc(ctx, func() { h.draining.Store(true) })
+		defer stop()
+		return run(ctx)
+	}
+}
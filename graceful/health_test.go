@@ -0,0 +1,71 @@
+package graceful
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+	h := HealthServer("127.0.0.1:0")
+	probe := func(path string) int {
+		rec := httptest.NewRecorder()
+		h.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if code := probe("/readyz"); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before run, got %d", code)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	running := make(chan struct{})
+	release := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- h.Run(func(ctx context.Context) error {
+			close(running)
+			<-ctx.Done()
+			<-release
+			return nil
+		})(ctx)
+	}()
+
+	<-running
+	if code := probe("/readyz"); code != http.StatusOK {
+		t.Fatalf("expected 200 while running, got %d", code)
+	}
+
+	cancel()
+	// Wait until the cancellation has been observed.
+	for h.Ready() {
+		runtime.Gosched()
+	}
+	if code := probe("/readyz"); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 while draining, got %d", code)
+	}
+	if code := probe("/livez"); code != http.StatusOK {
+		t.Fatalf("expected 200 from liveness probe while draining, got %d", code)
+	}
+
+	close(release)
+	if err := <-errCh; err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
+
+func TestHealth_ListenError(t *testing.T) {
+	called := false
+	err := HealthServer("invalid-address").Run(func(ctx context.Context) error {
+		called = true
+		return nil
+	})(context.Background())
+	if err == nil {
+		t.Fatal("expected listen error")
+	}
+	if called {
+		t.Fatal("expected run function not to be called")
+	}
+}
@@ -0,0 +1,11 @@
+module github.com/pressly/cli/graceful/winsvc
+
+go 1.21.0
+
+require (
+	github.com/pressly/cli v0.0.0-00010101000000-000000000000
+	golang.org/x/sys v0.28.0
+)
+
+// Develop against the cli module in this repository.
+replace github.com/pressly/cli => ../..
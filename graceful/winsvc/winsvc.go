@@ -0,0 +1,36 @@
+// Package winsvc runs a graceful run function as a Windows service, so the same binary can be
+// installed as a service or started from a console without changes.
+//
+// When the process is started by the Windows service control manager, Stop and Shutdown control
+// requests cancel the context passed to the run function, just as SIGINT or SIGTERM do for a
+// console process, and the graceful options (termination timeout, shutdown hooks, and so on) apply
+// unchanged. Otherwise, including on every other operating system, the run function is run with
+// signal handling as usual.
+//
+// winsvc is its own module, github.com/pressly/cli/graceful/winsvc, so that programs which don't
+// import it don't depend on golang.org/x/sys.
+//
+// Example:
+//
+//	func main() {
+//	    err := winsvc.Run("myservice", serve, graceful.WithTerminationTimeout(30*time.Second))
+//	    if err != nil {
+//	        log.Print(err)
+//	    }
+//	    os.Exit(graceful.ExitCode(err))
+//	}
+package winsvc
+
+import (
+	"context"
+
+	"github.com/pressly/cli/graceful"
+)
+
+// Run runs fn under the graceful lifecycle and returns its result, like [graceful.RunContext]. If
+// the process is running as a Windows service named name, shutdown is triggered by service Stop
+// and Shutdown requests instead of signals, and the service reports [graceful.ExitCode] of the
+// result as its exit code. Use [graceful.ExitCode] to map the returned error to a process exit code.
+func Run(name string, fn func(context.Context) error, opts ...graceful.Option) error {
+	return run(name, fn, opts)
+}
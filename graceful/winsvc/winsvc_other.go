@@ -0,0 +1,13 @@
+//go:build !windows
+
+package winsvc
+
+import (
+	"context"
+
+	"github.com/pressly/cli/graceful"
+)
+
+func run(_ string, fn func(context.Context) error, opts []graceful.Option) error {
+	return graceful.RunContext(context.Background(), fn, opts...)
+}
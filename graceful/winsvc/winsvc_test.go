@@ -0,0 +1,23 @@
+package winsvc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pressly/cli/graceful"
+)
+
+func TestRun_Console(t *testing.T) {
+	boom := errors.New("boom")
+	err := Run("test", func(ctx context.Context) error { return boom })
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+	if code := graceful.ExitCode(err); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if err := Run("test", func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+//go:build windows
+
+package winsvc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pressly/cli/graceful"
+	"golang.org/x/sys/windows/svc"
+)
+
+func run(name string, fn func(context.Context) error, opts []graceful.Option) error {
+	isService, err := svc.IsWindowsService()
+	if err != nil {
+		return err
+	}
+	if !isService {
+		return graceful.RunContext(context.Background(), fn, opts...)
+	}
+	h := &handler{fn: fn, opts: opts}
+	if err := svc.Run(name, h); err != nil {
+		return errors.Join(h.err, err)
+	}
+	return h.err
+}
+
+// handler implements svc.Handler, translating service control requests into context cancellation.
+type handler struct {
+	fn   func(context.Context) error
+	opts []graceful.Option
+	err  error
+}
+
+func (h *handler) Execute(_ []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A service has no console, so the service control manager takes the place of signals.
+	opts := append(h.opts[:len(h.opts):len(h.opts)], graceful.WithSignals())
+	done := make(chan error, 1)
+	go func() {
+		done <- graceful.RunContext(ctx, h.fn, opts...)
+	}()
+
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for {
+		select {
+		case err := <-done:
+			h.err = err
+			status <- svc.Status{State: svc.StopPending}
+			if code := graceful.ExitCode(err); code != 0 {
+				// Report the code as service-specific so it shows up as-is in the event log.
+				return true, uint32(code)
+			}
+			return false, 0
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				status <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				cancel()
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pressly/cli/graceful"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGracefulExec(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns exec result", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{
+			Name: "serve",
+			Exec: GracefulExec(func(ctx context.Context, s *State) error {
+				return errors.New("boom")
+			}),
+		}
+		err := ParseAndRun(context.Background(), root, nil, nil)
+		require.EqualError(t, err, "boom")
+	})
+	t.Run("context cancellation starts graceful shutdown", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var stderr bytes.Buffer
+		root := &Command{
+			Name: "serve",
+			Exec: GracefulExec(func(ctx context.Context, s *State) error {
+				<-ctx.Done()
+				return nil
+			}),
+		}
+		err := ParseAndRun(ctx, root, nil, &RunOptions{Stderr: &stderr})
+		require.NoError(t, err)
+		require.Contains(t, stderr.String(), "shutting down gracefully")
+	})
+	t.Run("shutdown timeout exit code", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var info CommandRunInfo
+		root := &Command{
+			Name: "serve",
+			Exec: GracefulExec(func(ctx context.Context, s *State) error {
+				select {}
+			}, graceful.WithTerminationTimeout(10*time.Millisecond)),
+		}
+		err := ParseAndRun(ctx, root, nil, &RunOptions{
+			Stderr:            &bytes.Buffer{},
+			OnCommandComplete: func(i CommandRunInfo) { info = i },
+		})
+		require.ErrorIs(t, err, graceful.ErrShutdownTimeout)
+		require.Equal(t, 124, info.ExitCode)
+	})
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// HelpJSON returns the help of the command hierarchy as indented JSON, for IDE integrations and
+// external help viewers. It describes the same command as [DefaultUsage]: the terminal command of
+// the parsed path, or root itself before parsing. [ParseAndRun] prints it when the help flag is given
+// the value json, as in --help=json.
+func HelpJSON(root *Command) ([]byte, error) {
+	if root == nil {
+		return nil, errors.New("root command is nil")
+	}
+	terminalCmd := root.terminal()
+	path := terminalCmd.Name
+	if root.state != nil && len(root.state.path) > 0 {
+		path = getCommandPath(root.state.path)
+	}
+	help := jsonHelp{
+		Name:        terminalCmd.Name,
+		Path:        path,
+		Usage:       usageLine(root, terminalCmd),
+		ShortHelp:   terminalCmd.ShortHelp,
+		LongHelp:    terminalCmd.LongHelp,
+		Example:     terminalCmd.Example,
+		Annotations: terminalCmd.Annotations,
+	}
+	for _, sub := range terminalCmd.SubCommands {
+		help.Commands = append(help.Commands, jsonHelpCommand{
+			Name:      sub.Name,
+			ShortHelp: sub.ShortHelp,
+		})
+	}
+	for _, f := range usageFlags(root, terminalCmd) {
+		help.Flags = append(help.Flags, jsonHelpFlag{
+			Name:       strings.TrimPrefix(f.name, "--"),
+			Short:      f.short,
+			Type:       f.typeName,
+			Usage:      f.usage,
+			Default:    f.defval,
+			Constraint: f.describe,
+			EnvVar:     f.envVar,
+			Section:    f.section,
+			Required:   f.required,
+			Inherited:  f.inherited,
+			Negatable:  f.negatable,
+		})
+	}
+	return json.MarshalIndent(help, "", "  ")
+}
+
+type jsonHelp struct {
+	Name        string            `json:"name"`
+	Path        string            `json:"path"`
+	Usage       string            `json:"usage"`
+	ShortHelp   string            `json:"short_help,omitempty"`
+	LongHelp    string            `json:"long_help,omitempty"`
+	Example     string            `json:"example,omitempty"`
+	Commands    []jsonHelpCommand `json:"commands,omitempty"`
+	Flags       []jsonHelpFlag    `json:"flags,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type jsonHelpCommand struct {
+	Name      string `json:"name"`
+	ShortHelp string `json:"short_help,omitempty"`
+}
+
+type jsonHelpFlag struct {
+	Name       string `json:"name"`
+	Short      string `json:"short,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Usage      string `json:"usage,omitempty"`
+	Default    string `json:"default,omitempty"`
+	Constraint string `json:"constraint,omitempty"`
+	EnvVar     string `json:"env,omitempty"`
+	Section    string `json:"section,omitempty"`
+	Required   bool   `json:"required,omitempty"`
+	Inherited  bool   `json:"inherited,omitempty"`
+	Negatable  bool   `json:"negatable,omitempty"`
+}
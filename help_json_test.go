@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelpJSON(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func() *Command {
+		return &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("verbose", false, "verbose output")
+			}),
+			SubCommands: []*Command{{
+				Name:      "serve",
+				ShortHelp: "start the server",
+				Flags: FlagsFunc(func(f *flag.FlagSet) {
+					f.Int("port", 8080, "listen port")
+				}),
+				FlagOptions: []FlagOption{{Name: "port", Short: "p", EnvVar: "APP_PORT"}},
+				Annotations: map[string]string{"group": "server"},
+				Exec:        func(ctx context.Context, s *State) error { return nil },
+			}},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+	}
+
+	t.Run("help flag value", func(t *testing.T) {
+		t.Parallel()
+		var stdout bytes.Buffer
+		err := ParseAndRun(context.Background(), newRoot(), []string{"serve", "--help=json"}, &RunOptions{Stdout: &stdout})
+		require.NoError(t, err)
+		want := `{
+  "name": "serve",
+  "path": "app serve",
+  "usage": "app serve [flags]",
+  "short_help": "start the server",
+  "flags": [
+    {
+      "name": "help",
+      "short": "h",
+      "usage": "show help for serve"
+    },
+    {
+      "name": "port",
+      "short": "p",
+      "type": "int",
+      "usage": "listen port",
+      "default": "8080",
+      "env": "APP_PORT"
+    },
+    {
+      "name": "verbose",
+      "usage": "verbose output",
+      "default": "false",
+      "inherited": true
+    }
+  ],
+  "annotations": {
+    "group": "server"
+  }
+}
+`
+		require.Equal(t, want, stdout.String())
+	})
+	t.Run("before parsing", func(t *testing.T) {
+		t.Parallel()
+		data, err := HelpJSON(newRoot())
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"commands": [
+    {
+      "name": "serve",
+      "short_help": "start the server"
+    }
+  ]`)
+	})
+	t.Run("nil root", func(t *testing.T) {
+		t.Parallel()
+		_, err := HelpJSON(nil)
+		require.Error(t, err)
+	})
+}
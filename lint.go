@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// Issue is a problem in a command tree reported by [Validate].
+type Issue struct {
+	// Path is the path of command names from the root to the command with the issue, like
+	// ["todo", "list"].
+	Path []string
+	// Flag is the name of the flag the issue is about, or "" if it is about the command.
+	Flag string
+	// Message describes the issue.
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Flag != "" {
+		return fmt.Sprintf("%s: flag %s: %s", strings.Join(i.Path, " "), formatFlagName(i.Flag), i.Message)
+	}
+	return fmt.Sprintf("%s: %s", strings.Join(i.Path, " "), i.Message)
+}
+
+// Validate checks a command tree for mistakes and returns every issue it finds, in tree order. It
+// reports the errors [Parse] would return for invalid definitions, plus problems Parse tolerates:
+//
+//   - commands without a ShortHelp
+//   - commands without subcommands that have no Exec function
+//   - flags without usage text
+//   - flags that shadow a flag inherited from an ancestor
+//   - short aliases that conflict with one inherited from an ancestor
+//   - a Usage string that does not start with the command's path
+//
+// It is meant to be run from a test, so mistakes are caught before a user hits them:
+//
+//	func TestCommands(t *testing.T) {
+//	    for _, issue := range cli.Validate(newRootCommand()) {
+//	        t.Error(issue)
+//	    }
+//	}
+func Validate(root *Command) []Issue {
+	if root == nil {
+		return nil
+	}
+	var issues []Issue
+	lintCommand(root, nil, &issues)
+	return issues
+}
+
+// lintCommand appends the issues of cmd and its subcommands to issues. ancestors are the commands
+// above cmd, from the root.
+func lintCommand(cmd *Command, ancestors []*Command, issues *[]Issue) {
+	path := make([]string, 0, len(ancestors)+1)
+	for _, c := range ancestors {
+		path = append(path, c.Name)
+	}
+	path = append(path, cmd.Name)
+	root := cmd
+	if len(ancestors) > 0 {
+		root = ancestors[0]
+	}
+	report := func(flagName, format string, args ...any) {
+		*issues = append(*issues, Issue{Path: path, Flag: flagName, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if cmd.Name == "" {
+		report("", "command has no name")
+	}
+	for _, validate := range []func(*Command) error{
+		validateHelpFlag,
+		validateFlagOptions,
+		func(cmd *Command) error { return validateSubCommandNames(cmd, root.MatchCase.foldCommands()) },
+	} {
+		if err := validate(cmd); err != nil {
+			report("", "%v", err)
+		}
+	}
+	if cmd.Name != "" {
+		if err := validateName(cmd); err != nil {
+			report("", "%v", err)
+		}
+	}
+	if cmd.ShortHelp == "" {
+		report("", "missing ShortHelp")
+	}
+	if len(cmd.SubCommands) == 0 && cmd.Exec == nil {
+		report("", "command has no subcommands and no Exec function")
+	}
+	if usage := strings.Join(path, " "); cmd.Usage != "" && cmd.Usage != usage && !strings.HasPrefix(cmd.Usage, usage+" ") {
+		report("", "Usage %q does not start with the command path %q", cmd.Usage, usage)
+	}
+
+	// Flags and short aliases inherited from ancestors, mapped to the name of the command that
+	// defines them.
+	inherited := make(map[string]string)
+	inheritedShorts := make(map[string]string) // short -> flag name
+	for _, a := range ancestors {
+		local := localFlagSet(a)
+		if a.Flags != nil {
+			a.Flags.VisitAll(func(f *flag.Flag) {
+				if !local[f.Name] {
+					inherited[f.Name] = a.Name
+				}
+			})
+		}
+		for _, fo := range a.FlagOptions {
+			if fo.Short != "" && !local[fo.Name] {
+				inheritedShorts[fo.Short] = fo.Name
+			}
+		}
+	}
+	if cmd.Flags != nil {
+		cmd.Flags.VisitAll(func(f *flag.Flag) {
+			if f.Usage == "" {
+				report(f.Name, "missing usage text")
+			}
+			if owner, ok := inherited[f.Name]; ok {
+				report(f.Name, "shadows the flag inherited from %q", owner)
+			}
+		})
+	}
+	for _, fo := range cmd.FlagOptions {
+		if other, ok := inheritedShorts[fo.Short]; ok && fo.Short != "" && other != fo.Name {
+			report(fo.Name, "short alias %q is already used by inherited flag %s", fo.Short, formatFlagName(other))
+		}
+	}
+
+	ancestors = append(ancestors, cmd)
+	for _, sub := range cmd.SubCommands {
+		lintCommand(sub, ancestors, issues)
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	exec := func(ctx context.Context, s *State) error { return nil }
+
+	t.Run("clean tree", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{
+			Name:      "todo",
+			ShortHelp: "manage tasks",
+			Usage:     "todo <command> [flags]",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("verbose", false, "verbose output")
+			}),
+			FlagOptions: []FlagOption{{Name: "verbose", Short: "v"}},
+			SubCommands: []*Command{{
+				Name:      "list",
+				ShortHelp: "list tasks",
+				Usage:     "todo list [flags]",
+				Exec:      exec,
+			}},
+		}
+		require.Empty(t, Validate(root))
+	})
+	t.Run("issues", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{
+			Name:      "todo",
+			ShortHelp: "manage tasks",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("verbose", false, "verbose output")
+				f.String("output", "", "")
+			}),
+			FlagOptions: []FlagOption{{Name: "verbose", Short: "v"}},
+			SubCommands: []*Command{
+				{
+					Name:  "list",
+					Usage: "todo ls [flags]",
+					Flags: FlagsFunc(func(f *flag.FlagSet) {
+						f.String("output", "", "output file")
+						f.Bool("version", false, "show version")
+					}),
+					FlagOptions: []FlagOption{{Name: "version", Short: "v"}},
+					Exec:        exec,
+				},
+				{
+					Name:      "add",
+					ShortHelp: "add a task",
+					FlagOptions: []FlagOption{
+						{Name: "missing"},
+					},
+				},
+			},
+		}
+		var got []string
+		for _, issue := range Validate(root) {
+			got = append(got, issue.String())
+		}
+		require.Equal(t, []string{
+			"todo: flag -output: missing usage text",
+			"todo list: missing ShortHelp",
+			`todo list: Usage "todo ls [flags]" does not start with the command path "todo list"`,
+			`todo list: flag -output: shadows the flag inherited from "todo"`,
+			`todo list: flag -version: short alias "v" is already used by inherited flag -verbose`,
+			`todo add: flag option references unknown flag "missing"`,
+			"todo add: command has no subcommands and no Exec function",
+		}, got)
+	})
+}
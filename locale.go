@@ -0,0 +1,44 @@
+package cli
+
+import "sync/atomic"
+
+// translator holds the function installed by SetTranslator, or nil.
+var translator atomic.Pointer[func(string) string]
+
+// SetTranslator installs fn to translate the messages the package shows to users, so a CLI in
+// another language does not mix in English. It covers the headings and notes of [DefaultUsage],
+// like "Usage:", "Available Commands:", and "(required)", and usage errors from [Parse], like
+// "unknown command %q" and "required flag %q not set". Messages about invalid command definitions,
+// meant for the developer, and errors from the flag package itself stay in English.
+//
+// fn receives the English message, or for messages with values its format string, like
+// "unknown command %q", and returns the translation. A translated format string must use the same
+// verbs, possibly reordered with explicit argument indexes like %[2]s. Returning "" keeps the
+// English message:
+//
+//	catalog := map[string]string{
+//	    "Usage:":             "Uso:",
+//	    "unknown command %q": "comando desconocido %q",
+//	}
+//	cli.SetTranslator(func(msg string) string { return catalog[msg] })
+//
+// SetTranslator is safe to call concurrently with parsing, but is meant to be called once, before
+// parsing. Passing nil restores the English messages.
+func SetTranslator(fn func(msg string) string) {
+	if fn == nil {
+		translator.Store(nil)
+		return
+	}
+	translator.Store(&fn)
+}
+
+// tr returns the translation of msg from the translator installed with SetTranslator, or msg
+// itself.
+func tr(msg string) string {
+	if fn := translator.Load(); fn != nil {
+		if t := (*fn)(msg); t != "" {
+			return t
+		}
+	}
+	return msg
+}
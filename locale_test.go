@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTranslator(t *testing.T) {
+	// Not parallel: the translator is global.
+	catalog := map[string]string{
+		"Usage:":                          "Uso:",
+		"Flags:":                          "Opciones:",
+		"show help for %s":                "mostrar ayuda de %s",
+		"required":                        "obligatorio",
+		"unknown command %q":              "comando desconocido %q",
+		"required flag %q not set":        "falta la opción obligatoria %q",
+		"argument %d: invalid integer %q": "argumento %[1]d: %[2]q no es un número entero",
+	}
+	SetTranslator(func(msg string) string { return catalog[msg] })
+	t.Cleanup(func() { SetTranslator(nil) })
+
+	newRoot := func() *Command {
+		return &Command{
+			Name:      "app",
+			ShortHelp: "una aplicación",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.String("name", "", "nombre")
+			}),
+			FlagOptions: []FlagOption{{Name: "name", Required: true}},
+			SubCommands: []*Command{{
+				Name: "get",
+				Exec: func(ctx context.Context, s *State) error {
+					_, err := s.ArgInt(0)
+					return err
+				},
+			}},
+		}
+	}
+
+	root := newRoot()
+	require.ErrorIs(t, Parse(root, []string{"--help"}), ErrHelp)
+	want := `una aplicación
+
+Uso:
+  app <command> [flags]
+
+Available Commands:
+  get
+
+Opciones:
+  -h, --help           mostrar ayuda de app
+      --name string    nombre (obligatorio)
+
+Use "app [command] --help" for more information about a command.`
+	require.Equal(t, want, DefaultUsage(root))
+
+	err := Parse(newRoot(), []string{"put"})
+	require.EqualError(t, err, `comando desconocido "put"`)
+	err = Parse(newRoot(), []string{"get"})
+	require.EqualError(t, err, `command "app get": falta la opción obligatoria "-name"`)
+	err = ParseAndRun(context.Background(), newRoot(), []string{"get", "--name=x", "one"}, nil)
+	require.EqualError(t, err, `argumento 1: "one" no es un número entero`)
+
+	SetTranslator(nil)
+	err = Parse(newRoot(), []string{"put"})
+	require.EqualError(t, err, `unknown command "put"`)
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"flag"
+	"io"
+	"log/slog"
+
+	"github.com/pressly/cli/flagtype"
+)
+
+const (
+	logLevelFlag  = "log-level"
+	logFormatFlag = "log-format"
+)
+
+// LogFlags registers the standard --log-level and --log-format flags on f. Register them on the
+// root command so every subcommand inherits them:
+//
+//	root.Flags = cli.FlagsFunc(func(f *flag.FlagSet) {
+//	    cli.LogFlags(f)
+//	})
+//
+// When these flags are present and [RunOptions] has no Logger, [Run] builds [State].Logger from
+// them, writing to [State].Stderr. The level is a [flagtype.LogLevel], accepting debug, info, warn,
+// or error, and is retrieved as [slog.Level]. The format accepts text or json and is retrieved as a
+// string.
+func LogFlags(f *flag.FlagSet) {
+	f.Var(flagtype.LogLevel(), logLevelFlag, "log level (debug, info, warn, error)")
+	f.Var(flagtype.EnumDefault("text", []string{"text", "json"}), logFormatFlag, "log format")
+}
+
+// resolveLogger returns the logger for a run. An explicit logger in the options always wins.
+// Otherwise a handler is built from the --log-level and --log-format flags, if registered anywhere
+// in the command path, falling back to an info-level text handler.
+func resolveLogger(s *State, opt *RunOptions) *slog.Logger {
+	if opt.Logger != nil {
+		return opt.Logger
+	}
+	level, format := slog.LevelInfo, "text"
+	// Walk from the root so flags on deeper commands take precedence, matching combineFlags.
+	for _, cmd := range s.path {
+		fs := cmd.Flags
+		if fs == nil {
+			continue
+		}
+		if f := fs.Lookup(logLevelFlag); f != nil {
+			if getter, ok := f.Value.(flag.Getter); ok {
+				if v, ok := getter.Get().(slog.Level); ok {
+					level = v
+				}
+			}
+		}
+		if f := fs.Lookup(logFormatFlag); f != nil {
+			if getter, ok := f.Value.(flag.Getter); ok {
+				if v, ok := getter.Get().(string); ok && v != "" {
+					format = v
+				}
+			}
+		}
+	}
+	return newLogger(s.Stderr, level, format)
+}
+
+func newLogger(w io.Writer, level slog.Level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if format == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
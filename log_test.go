@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func() *Command {
+		return &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				LogFlags(f)
+			}),
+			SubCommands: []*Command{{
+				Name: "sync",
+				Exec: func(ctx context.Context, s *State) error {
+					s.Logger.Debug("debug message")
+					s.Logger.Info("info message", "items", 3)
+					return nil
+				},
+			}},
+		}
+	}
+
+	t.Run("default logger writes text to stderr", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{
+			Name: "app",
+			Exec: func(ctx context.Context, s *State) error {
+				s.Logger.Info("hello")
+				return nil
+			},
+		}
+		require.NoError(t, Parse(root, nil))
+		var stderr bytes.Buffer
+		require.NoError(t, Run(context.Background(), root, &RunOptions{Stderr: &stderr}))
+		assert.Contains(t, stderr.String(), "level=INFO msg=hello")
+	})
+	t.Run("explicit logger wins", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		require.NoError(t, Parse(root, []string{"sync", "--log-level=error"}))
+		var buf, stderr bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		require.NoError(t, Run(context.Background(), root, &RunOptions{Stderr: &stderr, Logger: logger}))
+		assert.Contains(t, buf.String(), "debug message")
+		assert.Empty(t, stderr.String())
+	})
+	t.Run("logger set on state after parse is kept", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		require.NoError(t, Parse(root, []string{"sync"}))
+		var buf, stderr bytes.Buffer
+		root.state.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		require.NoError(t, Run(context.Background(), root, &RunOptions{Stderr: &stderr}))
+		assert.Contains(t, buf.String(), "debug message")
+		assert.Empty(t, stderr.String())
+
+		// A logger built by a previous run is rebuilt from the new flags.
+		root = newRoot()
+		require.NoError(t, Parse(root, []string{"sync"}))
+		require.NoError(t, Run(context.Background(), root, &RunOptions{Stderr: &stderr}))
+		assert.NotContains(t, stderr.String(), "debug message")
+		require.NoError(t, Parse(root, []string{"sync", "--log-level=debug"}))
+		stderr.Reset()
+		require.NoError(t, Run(context.Background(), root, &RunOptions{Stderr: &stderr}))
+		assert.Contains(t, stderr.String(), "debug message")
+	})
+	t.Run("log level flag", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		require.NoError(t, Parse(root, []string{"sync", "--log-level", "DEBUG"}))
+		assert.Equal(t, slog.LevelDebug, GetFlag[slog.Level](root.state, "log-level"))
+		var stderr bytes.Buffer
+		require.NoError(t, Run(context.Background(), root, &RunOptions{Stderr: &stderr}))
+		assert.Contains(t, stderr.String(), "debug message")
+		assert.Contains(t, stderr.String(), "info message")
+	})
+	t.Run("log format flag", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		require.NoError(t, Parse(root, []string{"sync", "--log-format=json", "--log-level=warn"}))
+		var stderr bytes.Buffer
+		require.NoError(t, Run(context.Background(), root, &RunOptions{Stderr: &stderr}))
+		assert.Empty(t, stderr.String())
+
+		root = newRoot()
+		require.NoError(t, Parse(root, []string{"sync", "--log-format=json"}))
+		stderr.Reset()
+		require.NoError(t, Run(context.Background(), root, &RunOptions{Stderr: &stderr}))
+		assert.Contains(t, stderr.String(), `"msg":"info message","items":3`)
+	})
+	t.Run("invalid log level", func(t *testing.T) {
+		t.Parallel()
+		err := Parse(newRoot(), []string{"sync", "--log-level=loud"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid log level "loud"`)
+	})
+	t.Run("invalid log format", func(t *testing.T) {
+		t.Parallel()
+		err := Parse(newRoot(), []string{"sync", "--log-format=xml"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be one of: text, json")
+	})
+}
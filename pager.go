@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pressly/cli/xflag"
+)
+
+// defaultTerminalHeight is the assumed terminal height when LINES is not set.
+const defaultTerminalHeight = 24
+
+// writeHelp prints usage to the standard output of options, through a pager when
+// [RunOptions].HelpPager is set, the output is a terminal, and the text does not fit on one screen.
+// If the pager cannot be started the text is printed directly.
+func writeHelp(usage string, options *RunOptions) {
+	if options.HelpPager && isTerminal(options.Stdout) && strings.Count(usage, "\n")+1 >= terminalHeight() {
+		if err := page(usage, options.Stdout, options.Stderr); err == nil {
+			return
+		}
+	}
+	_, _ = fmt.Fprintln(options.Stdout, usage)
+}
+
+// page runs the pager named by $PAGER, or less if it is unset, with text as its input. Like git, it
+// sets LESS=FRX when LESS is unset so less exits if the text fits on one screen and keeps colors.
+func page(text string, stdout, stderr io.Writer) error {
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	args, err := xflag.SplitCommandString(pager)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("empty pager command")
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text + "\n")
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// The text has been handed to the pager; quitting it early is not an error worth reporting.
+	_ = cmd.Wait()
+	return nil
+}
+
+// terminalHeight returns the height of the terminal as reported by the LINES environment variable,
+// or 24 if it is unset or invalid.
+func terminalHeight() int {
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		return n
+	}
+	return defaultTerminalHeight
+}
+
+// isTerminal reports whether w is a character device, such as an interactive terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteHelp(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not a terminal", func(t *testing.T) {
+		t.Parallel()
+		var stdout bytes.Buffer
+		usage := strings.Repeat("line\n", 100) + "end"
+		writeHelp(usage, &RunOptions{Stdout: &stdout, HelpPager: true})
+		require.Equal(t, usage+"\n", stdout.String())
+	})
+}
+
+func TestPage(t *testing.T) {
+	t.Setenv("PAGER", "tr a-z A-Z")
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, page("usage:\n  app", &stdout, &stderr))
+	require.Equal(t, "USAGE:\n  APP\n", stdout.String())
+
+	t.Setenv("PAGER", "no-such-pager-command")
+	require.Error(t, page("usage", &stdout, &stderr))
+}
+
+func TestTerminalHeight(t *testing.T) {
+	t.Setenv("LINES", "50")
+	require.Equal(t, 50, terminalHeight())
+	t.Setenv("LINES", "tall")
+	require.Equal(t, defaultTerminalHeight, terminalHeight())
+}
@@ -5,6 +5,8 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"maps"
+	"os"
 	"regexp"
 	"slices"
 	"strconv"
@@ -19,13 +21,56 @@ import (
 // This function is the main entry point for parsing command-line arguments and should be called
 // with the root command and the arguments to parse, typically os.Args[1:]. Once parsing is
 // complete, the root command is ready to be executed with the [Run] function.
+//
+// Arguments of the form "@path" that appear before "--" are replaced with the arguments read from
+// the file at path, one or more per line, with shell-like quoting. This is useful for very long or
+// generated command lines. If no regular file exists at path the argument is kept as-is. Arguments
+// from the first positional argument of a command with AllowUnknownFlags or FlagsStopAtFirstArg
+// onwards are passed through unexpanded. Set [Command].DisableArgFiles on the root command to turn
+// this off.
 func Parse(root *Command, args []string) error {
+	return parse(root, args, parseConfig{
+		lookupEnv: os.LookupEnv,
+		debug:     debugOutput(nil, os.LookupEnv, os.Stderr),
+	})
+}
+
+// parseConfig holds the settings from [RunOptions] that affect parsing.
+type parseConfig struct {
+	// lookupEnv reads the environment variables of flags with an EnvVar.
+	lookupEnv func(string) (string, bool)
+	// dir is the base directory for flag values that resolve relative paths, or "" for the working
+	// directory.
+	dir string
+	// debug receives parse traces, or is nil when tracing is off.
+	debug io.Writer
+}
+
+// parse implements [Parse].
+func parse(root *Command, args []string, cfg parseConfig) error {
 	if root == nil {
 		return fmt.Errorf("failed to parse: root command is nil")
 	}
-	if err := validateCommands(root, nil); err != nil {
+	if err := validateCommands(root, nil, root.MatchCase.foldCommands()); err != nil {
 		return fmt.Errorf("failed to parse: %w", err)
 	}
+	if root.DisallowFlagShadowing {
+		if err := checkFlagShadowing(root, nil, nil); err != nil {
+			return fmt.Errorf("failed to parse: %w", err)
+		}
+	}
+	if root.PreParse != nil {
+		var err error
+		if args, err = root.PreParse(slices.Clone(args)); err != nil {
+			return fmt.Errorf("failed to parse: %w", err)
+		}
+	}
+	if !root.DisableArgFiles {
+		var err error
+		if args, err = expandArgFiles(root, args, cfg.dir); err != nil {
+			return fmt.Errorf("failed to parse: %w", err)
+		}
+	}
 
 	// Initialize or update root state
 	if root.state == nil {
@@ -35,37 +80,122 @@ func Parse(root *Command, args []string) error {
 	} else {
 		// Reset command path but preserve other state
 		root.state.path = []*Command{root}
+		root.state.helpAll = false
+		root.state.helpJSON = false
 	}
 
 	argsToParse, remainingArgs := splitAtDelimiter(args)
 
-	current, err := resolveCommandPath(root, argsToParse)
+	current, nameIdx, err := resolveCommandPath(root, argsToParse)
 	if err != nil {
 		return err
 	}
+	// The terminal command's own arguments begin just past its name.
+	argsStart := 0
+	if len(nameIdx) > 0 {
+		argsStart = nameIdx[len(nameIdx)-1] + 1
+	}
+	cfg.debugf("resolved command %q", getCommandPath(root.state.path))
+	// Share the state with the rest of the path so the terminal command, as carried by HelpError,
+	// can be passed to DefaultUsage.
+	for _, cmd := range root.state.path[1:] {
+		cmd.state = root.state
+	}
 	current.Flags.Usage = func() { /* suppress default usage */ }
 
+	if err := checkShortConflicts(root.state.path); err != nil {
+		return err
+	}
+
+	combinedFlags := combineFlags(root.state.path)
+
+	// For commands that stop flag parsing at their first positional argument, everything from that
+	// argument onwards (including flags and any "--") is passed through untouched.
+	if current.FlagsStopAtFirstArg {
+		if i := firstArgIndex(args, argsStart, combinedFlags); i >= 0 {
+			argsToParse, remainingArgs = args[:i], args[i:]
+		}
+	}
+	// Drop the command names by position, so they are neither parsed as flags nor passed through
+	// as positional args, wherever they appear among the flags.
+	argsToParse = removeIndexes(argsToParse, nameIdx)
+
 	// Check for help flags after resolving the correct command
+	helpLong, helpShort := current.helpFlagNames(combinedFlags)
+	helpAll := helpAllFlagName(helpLong, combinedFlags)
 	for _, arg := range argsToParse {
-		if arg == "-h" || arg == "--h" || arg == "-help" || arg == "--help" {
-			// Combine flags first so the help message includes all inherited flags
-			combineFlags(root.state.path)
-			return ErrHelp
+		if isHelpArg(arg, helpLong, helpShort) {
+			cfg.debugf("help requested with %s", arg)
+			return &HelpError{Command: current}
+		}
+		if isHelpArg(arg, helpAll, "") {
+			root.state.helpAll = true
+			return &HelpError{Command: current}
+		}
+		if name, value, ok := strings.Cut(arg, "="); ok && value == "json" && isHelpArg(name, helpLong, "") {
+			root.state.helpJSON = true
+			return &HelpError{Command: current}
 		}
 	}
 
-	combinedFlags := combineFlags(root.state.path)
+	setBaseDir(combinedFlags, cfg.dir)
+	setLookupEnv(combinedFlags, cfg.lookupEnv)
+	if root.MatchCase.foldFlags() {
+		argsToParse = foldFlagArgs(argsToParse, combinedFlags)
+	}
+	argsToParse = expandRepeatedShorts(argsToParse, combinedFlags)
 
-	// Let ParseToEnd handle the flag parsing
-	if err := xflag.ParseToEnd(combinedFlags, argsToParse); err != nil {
-		return fmt.Errorf("command %q: %w", getCommandPath(root.state.path), err)
+	// For commands that accept unknown flags, set aside everything that is not a known flag so it
+	// is kept in order as positional args instead of failing to parse.
+	var passthrough []string
+	if current.AllowUnknownFlags {
+		argsToParse, passthrough = splitUnknownFlags(argsToParse, combinedFlags)
 	}
 
-	if err := checkRequiredFlags(root.state.path, combinedFlags); err != nil {
-		return err
+	// Let ParseToEnd handle the flag parsing. Parsing continues past bad flags, and the errors are
+	// reported together with invalid environment variables and missing required flags, so users
+	// can fix their command line in one go.
+	var errs []error
+	if err := xflag.ParseToEnd(combinedFlags, argsToParse, xflag.JoinErrors()); err != nil {
+		// The flag package treats undefined -h and -help as help requests on its own. With the help
+		// flag renamed or disabled they are ordinary unknown flags.
+		if errors.Is(err, flag.ErrHelp) {
+			err = fmt.Errorf("flag provided but not defined: %s", stdHelpArg(argsToParse))
+		}
+		sensitive := sensitiveFlagSet(root.state.path)
+		for _, err := range unjoin(err) {
+			err = redactParseError(err, sensitive)
+			errs = append(errs, usageErrorf("command %q: %w", getCommandPath(root.state.path), err))
+		}
 	}
 
-	root.state.Args = collectArgs(root.state.path, combinedFlags.Args(), remainingArgs)
+	// given holds the flags the user supplied a value for, even an invalid one, so they are not
+	// also reported as missing.
+	fromArgs := explicitFlags(root.state.path, combinedFlags)
+	given := maps.Clone(fromArgs)
+	for _, err := range errs {
+		if name := failedFlagName(err, root.state.path); name != "" {
+			given[name] = true
+		}
+	}
+	fromEnv, err := applyEnvFlags(root.state.path, combinedFlags, cfg.lookupEnv, given)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	cfg.traceFlags(root.state, fromArgs, fromEnv)
+
+	if err := checkRequiredFlags(root.state.path, combinedFlags, given); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	parsed := combinedFlags.Args()
+	if current.AllowUnknownFlags {
+		parsed = passthrough
+	}
+	root.state.Args = collectArgs(parsed, remainingArgs)
 
 	if current.Exec == nil {
 		return fmt.Errorf("command %q: no exec function defined", getCommandPath(root.state.path))
@@ -73,6 +203,58 @@ func Parse(root *Command, args []string) error {
 	return nil
 }
 
+// stdHelpArg returns the first flag in args that the flag package interprets as a help request,
+// formatted the way the flag package reports unknown flags.
+func stdHelpArg(args []string) string {
+	for _, arg := range args {
+		if isHelpArg(arg, "help", "h") {
+			name, _ := splitFlagArg(arg)
+			return formatFlagName(name)
+		}
+	}
+	return formatFlagName("help")
+}
+
+// setBaseDir passes dir to every flag value that resolves relative paths, such as flagtype.Path,
+// through a SetBaseDir method. An empty dir restores the working directory as the base.
+func setBaseDir(fs *flag.FlagSet, dir string) {
+	fs.VisitAll(func(f *flag.Flag) {
+		v := f.Value
+		for {
+			if s, ok := v.(interface{ SetBaseDir(string) }); ok {
+				s.SetBaseDir(dir)
+				return
+			}
+			u, ok := v.(interface{ Unwrap() flag.Value })
+			if !ok {
+				return
+			}
+			v = u.Unwrap()
+		}
+	})
+}
+
+// setLookupEnv passes lookupEnv to every flag value that reads environment variables while it is
+// set, such as flagtype.ExpandedString, through a SetLookupEnv method.
+func setLookupEnv(fs *flag.FlagSet, lookupEnv func(string) (string, bool)) {
+	fs.VisitAll(func(f *flag.Flag) {
+		v := f.Value
+		for {
+			if s, ok := v.(interface {
+				SetLookupEnv(func(string) (string, bool))
+			}); ok {
+				s.SetLookupEnv(lookupEnv)
+				return
+			}
+			u, ok := v.(interface{ Unwrap() flag.Value })
+			if !ok {
+				return
+			}
+			v = u.Unwrap()
+		}
+	})
+}
+
 // splitAtDelimiter splits args at the first "--" delimiter. Returns the args before the delimiter
 // and any args after it.
 func splitAtDelimiter(args []string) (argsToParse, remaining []string) {
@@ -85,59 +267,30 @@ func splitAtDelimiter(args []string) (argsToParse, remaining []string) {
 }
 
 // resolveCommandPath walks argsToParse to resolve the subcommand chain, building root.state.path
-// and initializing flag sets along the way. Returns the terminal (deepest) command.
-func resolveCommandPath(root *Command, argsToParse []string) (*Command, error) {
+// and initializing flag sets along the way. Returns the terminal (deepest) command and the indexes
+// in argsToParse of the resolved subcommand names, in path order.
+func resolveCommandPath(root *Command, argsToParse []string) (*Command, []int, error) {
 	current := root
 	if current.Flags == nil {
 		current.Flags = flag.NewFlagSet(root.Name, flag.ContinueOnError)
 	}
 
+	var nameIdx []int
 	i := 0
 	for i < len(argsToParse) {
 		arg := argsToParse[i]
 
 		// Skip flags and their values
 		if strings.HasPrefix(arg, "-") {
-			// For formats like -flag=x or --flag=x
-			if strings.Contains(arg, "=") {
+			// For formats like -o=x, -flag=x or --flag=x the value is inline, so there is nothing
+			// further to skip. This applies equally to long names and short aliases.
+			name, hasValue := splitFlagArg(arg)
+			if hasValue {
 				i++
 				continue
 			}
 
-			// Check if this flag expects a value across all commands in the chain (not just the
-			// current command), since flags from ancestor commands are inherited and can appear
-			// anywhere. Also check short flag aliases from FlagOptions.
-			name := strings.TrimLeft(arg, "-")
-			skipValue := false
-			for _, cmd := range root.state.path {
-				localFlags := localFlagSet(cmd.FlagOptions)
-				// Skip local flags on ancestor commands (any command already in the path is an
-				// ancestor of the not-yet-resolved terminal command).
-				if localFlags[name] {
-					continue
-				}
-				// First try direct lookup.
-				f := cmd.Flags.Lookup(name)
-				// If not found, check if it's a short alias.
-				if f == nil {
-					for _, fm := range cmd.FlagOptions {
-						if fm.Short == name {
-							if localFlags[fm.Name] {
-								break
-							}
-							f = cmd.Flags.Lookup(fm.Name)
-							break
-						}
-					}
-				}
-				if f != nil {
-					if _, isBool := f.Value.(interface{ IsBoolFlag() bool }); !isBool {
-						skipValue = true
-					}
-					break
-				}
-			}
-			if skipValue {
+			if flagTakesValue(root.state.path, name, root.MatchCase.foldFlags()) {
 				// Skip both flag and its value
 				i += 2
 				continue
@@ -148,20 +301,111 @@ func resolveCommandPath(root *Command, argsToParse []string) (*Command, error) {
 
 		// Try to traverse to subcommand
 		if len(current.SubCommands) > 0 {
-			if sub := current.findSubCommand(arg); sub != nil {
+			sub := current.findSubCommand(arg, root.MatchCase.foldCommands())
+			if sub == nil && root.PrefixMatching {
+				var err error
+				if sub, err = current.findSubCommandByPrefix(arg, root.MatchCase.foldCommands()); err != nil {
+					return nil, nil, err
+				}
+			}
+			if sub != nil {
 				root.state.path = append(slices.Clone(root.state.path), sub)
 				if sub.Flags == nil {
 					sub.Flags = flag.NewFlagSet(sub.Name, flag.ContinueOnError)
 				}
 				current = sub
+				nameIdx = append(nameIdx, i)
 				i++
 				continue
 			}
-			return nil, current.formatUnknownCommandError(arg)
+			return nil, nil, current.formatUnknownCommandError(arg, root.SuggestDepth)
 		}
 		break
 	}
-	return current, nil
+	return current, nameIdx, nil
+}
+
+// flagTakesValue reports whether the flag name, written without dashes and possibly a short alias,
+// expects a value in the next argument. It checks every command in path, not just the last one,
+// since flags from ancestor commands are inherited and can appear anywhere. Local flags are
+// skipped, since any command in the path is an ancestor of the not-yet-resolved terminal command.
+func flagTakesValue(path []*Command, name string, fold bool) bool {
+	for _, cmd := range path {
+		if cmd.Flags == nil {
+			continue
+		}
+		localFlags := localFlagSet(cmd)
+		if localFlags[name] {
+			continue
+		}
+		// First try direct lookup.
+		f := cmd.Flags.Lookup(name)
+		if f == nil && fold {
+			f = foldLookup(cmd.Flags, name)
+		}
+		// If not found, check if it's a short alias.
+		if f == nil {
+			for _, fm := range cmd.FlagOptions {
+				if equalName(fm.Short, name, fold) {
+					if localFlags[fm.Name] {
+						break
+					}
+					f = cmd.Flags.Lookup(fm.Name)
+					break
+				}
+			}
+		}
+		if f != nil {
+			return !isBoolFlag(f)
+		}
+	}
+	return false
+}
+
+// splitUnknownFlags separates args into known flags (with their values) and everything else, which
+// includes positional arguments and flags not defined in fs. The second slice preserves the
+// original order. The value of an unknown flag cannot be told apart from a positional argument
+// unless it is written inline (--unknown=value), so it is kept as a separate element.
+func splitUnknownFlags(args []string, fs *flag.FlagSet) (known, rest []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' {
+			rest = append(rest, arg)
+			continue
+		}
+		name, hasValue := splitFlagArg(arg)
+		f := fs.Lookup(name)
+		if f == nil {
+			rest = append(rest, arg)
+			continue
+		}
+		known = append(known, arg)
+		if !hasValue && !isBoolFlag(f) && i+1 < len(args) {
+			i++
+			known = append(known, args[i])
+		}
+	}
+	return known, rest
+}
+
+// firstArgIndex returns the index of the first positional argument in args at or after start,
+// skipping flags and the values of flags that take one. Returns -1 if a "--" delimiter or the end of
+// args is reached first.
+func firstArgIndex(args []string, start int, fs *flag.FlagSet) int {
+	for i := start; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return -1
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			return i
+		}
+		name, hasValue := splitFlagArg(arg)
+		if f := fs.Lookup(name); f != nil && !hasValue && !isBoolFlag(f) {
+			i++
+		}
+	}
+	return -1
 }
 
 // combineFlags merges flags from the command path into a single FlagSet. Flags are added in reverse
@@ -176,8 +420,9 @@ func combineFlags(path []*Command) *flag.FlagSet {
 		if cmd.Flags == nil {
 			continue
 		}
-		localFlags := localFlagSet(cmd.FlagOptions)
+		localFlags := localFlagSet(cmd)
 		shortMap := shortFlagMap(cmd.FlagOptions)
+		negatable := negatableFlagSet(cmd.FlagOptions)
 		isAncestor := i < terminalIdx
 		cmd.Flags.VisitAll(func(f *flag.Flag) {
 			// Skip local flags from ancestor commands — they are not inherited.
@@ -193,22 +438,172 @@ func combineFlags(path []*Command) *flag.FlagSet {
 					combined.Var(f.Value, short, f.Usage)
 				}
 			}
+			// Register the --no-<name> form, which sets the same Value to the inverse.
+			if negatable[f.Name] {
+				if name := negatedName(f.Name); combined.Lookup(name) == nil {
+					combined.Var(&negatedValue{name: f.Name, v: f.Value}, name, f.Usage)
+				}
+			}
 		})
 	}
 	return combined
 }
 
-// localFlagSet builds a set of flag names that are marked as local in FlagOptions.
-func localFlagSet(options []FlagOption) map[string]bool {
+// foldFlagArgs rewrites flag arguments whose name matches a flag in fs only when ignoring case, like
+// --Verbose for --verbose, to use the flag's name. Values of flags that take a value are left
+// untouched.
+func foldFlagArgs(args []string, fs *flag.FlagSet) []string {
+	out := slices.Clone(args)
+	for i := 0; i < len(out); i++ {
+		arg := out[i]
+		if len(arg) < 2 || arg[0] != '-' {
+			continue
+		}
+		name, hasValue := splitFlagArg(arg)
+		f := fs.Lookup(name)
+		if f == nil {
+			if f = foldLookup(fs, name); f == nil {
+				continue
+			}
+			dashes := "-"
+			if strings.HasPrefix(arg, "--") {
+				dashes = "--"
+			}
+			out[i] = dashes + f.Name + arg[len(dashes)+len(name):]
+		}
+		if !hasValue && !isBoolFlag(f) {
+			i++
+		}
+	}
+	return out
+}
+
+// foldLookup returns the only flag in fs whose name equals name ignoring case, or nil.
+func foldLookup(fs *flag.FlagSet, name string) *flag.Flag {
+	var match *flag.Flag
+	var n int
+	fs.VisitAll(func(f *flag.Flag) {
+		if strings.EqualFold(f.Name, name) {
+			match = f
+			n++
+		}
+	})
+	if n != 1 {
+		return nil
+	}
+	return match
+}
+
+// expandRepeatedShorts rewrites a single-dash argument made of one repeated boolean flag name,
+// like -vvv, into separate occurrences (-v -v -v). This is what makes counting flags such as
+// flagtype.Count work with stacked short aliases. Arguments that are defined flags, values of flags
+// that take a value, or anything else are left untouched.
+func expandRepeatedShorts(args []string, fs *flag.FlagSet) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		out = append(out, arg)
+		if len(arg) < 2 || arg[0] != '-' {
+			continue
+		}
+		name, hasValue := splitFlagArg(arg)
+		if hasValue {
+			continue
+		}
+		if f := fs.Lookup(name); f != nil {
+			if !isBoolFlag(f) && i+1 < len(args) {
+				// Keep the flag's value as-is, even if it looks like stacked shorts.
+				i++
+				out = append(out, args[i])
+			}
+			continue
+		}
+		if arg[1] == '-' || len(name) < 2 || strings.Count(name, name[:1]) != len(name) {
+			continue
+		}
+		if f := fs.Lookup(name[:1]); f == nil || !isBoolFlag(f) {
+			continue
+		}
+		out[len(out)-1] = "-" + name[:1]
+		for j := 1; j < len(name); j++ {
+			out = append(out, "-"+name[:1])
+		}
+	}
+	return out
+}
+
+// splitFlagArg returns the flag name from an argument like -o, --output, -o=file.txt or
+// --output=file.txt, and whether the argument carries an inline value after "=". Short aliases are
+// registered as ordinary flags in the combined FlagSet, so the same rules apply to both forms.
+func splitFlagArg(arg string) (name string, hasValue bool) {
+	name = strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+	name, _, hasValue = strings.Cut(name, "=")
+	return name, hasValue
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	bf, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && bf.IsBoolFlag()
+}
+
+// localFlagSet builds a set of the command's flag names that are not inherited by child commands:
+// flags marked as local in FlagOptions or, with FlagsLocalByDefault, every flag not marked as
+// persistent.
+func localFlagSet(cmd *Command) map[string]bool {
+	m := make(map[string]bool, len(cmd.FlagOptions))
+	persistent := make(map[string]bool)
+	for _, fm := range cmd.FlagOptions {
+		if fm.Local {
+			m[fm.Name] = true
+		}
+		if fm.Persistent {
+			persistent[fm.Name] = true
+		}
+	}
+	if cmd.FlagsLocalByDefault && cmd.Flags != nil {
+		cmd.Flags.VisitAll(func(f *flag.Flag) {
+			if !persistent[f.Name] {
+				m[f.Name] = true
+			}
+		})
+	}
+	return m
+}
+
+// negatableFlagSet builds a set of flag names that are marked as negatable in FlagOptions.
+func negatableFlagSet(options []FlagOption) map[string]bool {
 	m := make(map[string]bool, len(options))
 	for _, fm := range options {
-		if fm.Local {
+		if fm.Negatable {
 			m[fm.Name] = true
 		}
 	}
 	return m
 }
 
+func negatedName(name string) string {
+	return "no-" + name
+}
+
+// negatedValue is the Value registered for the --no-<name> form of a negatable boolean flag. It
+// inverts whatever it is set to and forwards the result to the original flag's Value.
+type negatedValue struct {
+	name string // name of the original flag
+	v    flag.Value
+}
+
+func (n *negatedValue) String() string { return "false" }
+
+func (n *negatedValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	return n.v.Set(strconv.FormatBool(!b))
+}
+
+func (n *negatedValue) IsBoolFlag() bool { return true }
+
 // shortFlagMap builds a map from long flag name to short alias from FlagOptions.
 func shortFlagMap(options []FlagOption) map[string]string {
 	m := make(map[string]string, len(options))
@@ -220,69 +615,200 @@ func shortFlagMap(options []FlagOption) map[string]string {
 	return m
 }
 
-// checkRequiredFlags verifies that all flags marked as required in FlagOptions were explicitly set
-// during parsing.
-func checkRequiredFlags(path []*Command, combined *flag.FlagSet) error {
-	// Build a set of flags that were explicitly set during parsing. Visit (unlike VisitAll) only
-	// iterates over flags that were actually provided by the user, regardless of their value.
-	setFlags := make(map[string]struct{})
+// applyEnvFlags sets flags that have an EnvVar in FlagOptions and are not in given from their
+// environment variable, if it is set. It returns the names of the flags it set, mapped to their
+// environment variable, and adds every flag whose variable is set to given. Invalid values are
+// returned as one error per flag, joined with [errors.Join].
+//
+// Commands are visited from the terminal command up, and an ancestor's EnvVar is ignored when a
+// deeper command redefines the flag, matching the precedence of combineFlags.
+func applyEnvFlags(path []*Command, combined *flag.FlagSet, lookupEnv func(string) (string, bool), given map[string]bool) (map[string]string, error) {
+	fromEnv := make(map[string]string)
+	var errs []error
+	terminalIdx := len(path) - 1
+	// redefined holds the flags defined by the commands visited so far, which shadow the same
+	// flags on their ancestors.
+	redefined := make(map[string]bool)
+	for i := terminalIdx; i >= 0; i-- {
+		cmd := path[i]
+		local := localFlagSet(cmd)
+		isAncestor := i < terminalIdx
+		for _, fo := range cmd.FlagOptions {
+			if fo.EnvVar == "" || given[fo.Name] || redefined[fo.Name] || (local[fo.Name] && isAncestor) {
+				continue
+			}
+			val, ok := lookupEnv(fo.EnvVar)
+			if !ok {
+				continue
+			}
+			given[fo.Name] = true
+			if err := combined.Set(fo.Name, val); err != nil {
+				if fo.Sensitive && val != "" {
+					err = errors.New(strings.ReplaceAll(err.Error(), val, sensitiveMask))
+					val = sensitiveMask
+				}
+				errs = append(errs, fmt.Errorf("command %q: invalid value %q for flag %s from environment variable %s: %w",
+					getCommandPath(path), val, formatFlagName(fo.Name), fo.EnvVar, err))
+				continue
+			}
+			fromEnv[fo.Name] = fo.EnvVar
+		}
+		if cmd.Flags != nil {
+			cmd.Flags.VisitAll(func(f *flag.Flag) {
+				if !isAncestor || !local[f.Name] {
+					redefined[f.Name] = true
+				}
+			})
+		}
+	}
+	return fromEnv, errors.Join(errs...)
+}
+
+// explicitFlags returns the names of the flags set on the command line. Short aliases are resolved
+// to the flag's name, and --no-<name> counts as setting <name>.
+func explicitFlags(path []*Command, combined *flag.FlagSet) map[string]bool {
+	long := shortAliases(path)
+	set := make(map[string]bool)
+	// Visit (unlike VisitAll) only iterates over flags that were actually provided by the user,
+	// regardless of their value.
 	combined.Visit(func(f *flag.Flag) {
-		setFlags[f.Name] = struct{}{}
+		name := f.Name
+		if n, ok := f.Value.(*negatedValue); ok {
+			name = n.name
+		} else if l, ok := long[name]; ok {
+			name = l
+		}
+		set[name] = true
 	})
+	return set
+}
+
+// shortAliases maps the short aliases available to the terminal command of path to their flag's
+// name. Aliases of local flags on ancestor commands are left out.
+func shortAliases(path []*Command) map[string]string {
+	long := make(map[string]string)
+	terminalIdx := len(path) - 1
+	for i := terminalIdx; i >= 0; i-- {
+		local := localFlagSet(path[i])
+		for _, fo := range path[i].FlagOptions {
+			if fo.Short == "" || (local[fo.Name] && i < terminalIdx) {
+				continue
+			}
+			if _, ok := long[fo.Short]; !ok {
+				long[fo.Short] = fo.Name
+			}
+		}
+	}
+	return long
+}
+
+// flagErrorRegex matches the errors the flag package returns for a flag that was given but could
+// not be parsed: an invalid value or a missing value.
+var flagErrorRegex = regexp.MustCompile(`(?:invalid (?:boolean )?value "(?:[^"\\]|\\.)*" for (?:flag )?|flag needs an argument: )-([^:\s]+)`)
+
+// failedFlagName returns the name of the flag that err, a flag parse error, is about, or "" if it
+// is not about a flag that was given.
+func failedFlagName(err error, path []*Command) string {
+	m := flagErrorRegex.FindStringSubmatch(err.Error())
+	if m == nil {
+		return ""
+	}
+	if long, ok := shortAliases(path)[m[1]]; ok {
+		return long
+	}
+	return m[1]
+}
+
+// unjoin returns the errors joined in err by [errors.Join], or err itself.
+func unjoin(err error) []error {
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		return j.Unwrap()
+	}
+	return []error{err}
+}
+
+// checkShortConflicts returns an error if two different flags available to the terminal command
+// share a short alias, such as -v for --verbose on a parent and -v for --version on a child.
+// validateFlagOptions catches duplicates within a single command; this catches them across the
+// resolved command path.
+func checkShortConflicts(path []*Command) error {
+	type owner struct {
+		name string // flag name
+		path string // path of the command that defines the flag
+	}
+	seen := make(map[string]owner)
+	terminalIdx := len(path) - 1
+	for i, cmd := range path {
+		local := localFlagSet(cmd)
+		for _, fo := range cmd.FlagOptions {
+			if fo.Short == "" || (local[fo.Name] && i < terminalIdx) {
+				continue
+			}
+			o := owner{name: fo.Name, path: getCommandPath(path[:i+1])}
+			if prev, ok := seen[fo.Short]; ok && prev.name != fo.Name {
+				return fmt.Errorf("command %q: short flag %q used by both %q (from %q) and %q (from %q)",
+					getCommandPath(path), fo.Short, prev.name, prev.path, o.name, o.path)
+			}
+			seen[fo.Short] = o
+		}
+	}
+	return nil
+}
 
+// checkRequiredFlags verifies that all flags marked as required in FlagOptions are in given, the
+// flags supplied on the command line or through an environment variable.
+func checkRequiredFlags(path []*Command, combined *flag.FlagSet, given map[string]bool) error {
 	terminalIdx := len(path) - 1
 	var missingFlags []string
 	for i, cmd := range path {
+		local := localFlagSet(cmd)
 		for _, fo := range cmd.FlagOptions {
 			if !fo.Required {
 				continue
 			}
 			// Skip required-flag checks for local flags on ancestor commands.
-			if fo.Local && i < terminalIdx {
+			if local[fo.Name] && i < terminalIdx {
 				continue
 			}
 			if combined.Lookup(fo.Name) == nil {
 				return fmt.Errorf("command %q: internal error: required flag %s not found in flag set", getCommandPath(path), formatFlagName(fo.Name))
 			}
-			if _, ok := setFlags[fo.Name]; !ok {
+			if !given[fo.Name] {
 				missingFlags = append(missingFlags, formatFlagName(fo.Name))
 			}
 		}
 	}
 	if len(missingFlags) > 0 {
-		msg := "required flag"
+		msg := tr("required flag %q not set")
 		if len(missingFlags) > 1 {
-			msg += "s"
+			msg = tr("required flags %q not set")
 		}
-		return fmt.Errorf("command %q: %s %q not set", getCommandPath(path), msg, strings.Join(missingFlags, ", "))
+		return usageErrorf("command %q: "+msg, getCommandPath(path), strings.Join(missingFlags, ", "))
 	}
 	return nil
 }
 
-// collectArgs strips resolved command names from the parsed positional args and appends any args
-// that appeared after the "--" delimiter.
-func collectArgs(path []*Command, parsed, remaining []string) []string {
-	// Skip past command names in remaining args. Only strip the exact command names that were
-	// resolved during traversal (path[1:], since root never appears in user args), in order and
-	// only once each.
-	startIdx := 0
-	chainIdx := 1 // Skip root
-	for startIdx < len(parsed) && chainIdx < len(path) {
-		if strings.EqualFold(parsed[startIdx], path[chainIdx].Name) {
-			startIdx++
-			chainIdx++
-		} else {
-			break
+// removeIndexes returns a copy of args without the elements at the given ascending indexes.
+func removeIndexes(args []string, indexes []int) []string {
+	if len(indexes) == 0 {
+		return args
+	}
+	out := make([]string, 0, len(args)-len(indexes))
+	for i, arg := range args {
+		if len(indexes) > 0 && indexes[0] == i {
+			indexes = indexes[1:]
+			continue
 		}
+		out = append(out, arg)
 	}
+	return out
+}
 
+// collectArgs joins the parsed positional args with any args after the "--" delimiter.
+func collectArgs(parsed, remaining []string) []string {
 	var finalArgs []string
-	if startIdx < len(parsed) {
-		finalArgs = append(finalArgs, parsed[startIdx:]...)
-	}
-	if len(remaining) > 0 {
-		finalArgs = append(finalArgs, remaining...)
-	}
+	finalArgs = append(finalArgs, parsed...)
+	finalArgs = append(finalArgs, remaining...)
 	return finalArgs
 }
 
@@ -295,7 +821,9 @@ func validateName(root *Command) error {
 	return nil
 }
 
-func validateCommands(root *Command, path []string) error {
+// validateCommands checks root and its subcommands for definition errors. fold is whether
+// subcommand names are matched ignoring case, from the MatchCase of the root of the tree.
+func validateCommands(root *Command, path []string, fold bool) error {
 	if root.Name == "" {
 		if len(path) == 0 {
 			return errors.New("root command has no name")
@@ -304,33 +832,102 @@ func validateCommands(root *Command, path []string) error {
 	}
 
 	currentPath := append(path, root.Name)
-	if err := validateName(root); err != nil {
-		quoted := make([]string, len(currentPath))
-		for i, p := range currentPath {
-			quoted[i] = strconv.Quote(p)
+	validators := []func(*Command) error{
+		validateName,
+		validateHelpFlag,
+		validateFlagOptions,
+		func(cmd *Command) error { return validateSubCommandNames(cmd, fold) },
+	}
+	for _, validate := range validators {
+		if err := validate(root); err != nil {
+			quoted := make([]string, len(currentPath))
+			for i, p := range currentPath {
+				quoted[i] = strconv.Quote(p)
+			}
+			return fmt.Errorf("command [%s]: %w", strings.Join(quoted, ", "), err)
 		}
-		return fmt.Errorf("command [%s]: %w", strings.Join(quoted, ", "), err)
 	}
 
-	if err := validateFlagOptions(root); err != nil {
-		quoted := make([]string, len(currentPath))
-		for i, p := range currentPath {
-			quoted[i] = strconv.Quote(p)
+	for _, sub := range root.SubCommands {
+		if err := validateCommands(sub, currentPath, fold); err != nil {
+			return err
 		}
-		return fmt.Errorf("command [%s]: %w", strings.Join(quoted, ", "), err)
 	}
+	return nil
+}
 
-	for _, sub := range root.SubCommands {
-		if err := validateCommands(sub, currentPath); err != nil {
+// checkFlagShadowing returns an error if cmd or any of its subcommands defines a flag that is
+// already inherited from an ancestor. inherited maps flag names to the name of the command that
+// defines them.
+func checkFlagShadowing(cmd *Command, path []string, inherited map[string]string) error {
+	currentPath := append(slices.Clone(path), cmd.Name)
+	next := maps.Clone(inherited)
+	if next == nil {
+		next = make(map[string]string)
+	}
+	if cmd.Flags != nil {
+		local := localFlagSet(cmd)
+		var err error
+		cmd.Flags.VisitAll(func(f *flag.Flag) {
+			if owner, ok := inherited[f.Name]; ok && err == nil {
+				quoted := make([]string, len(currentPath))
+				for i, p := range currentPath {
+					quoted[i] = strconv.Quote(p)
+				}
+				err = fmt.Errorf("command [%s]: flag %q shadows the flag inherited from %q",
+					strings.Join(quoted, ", "), f.Name, owner)
+			}
+			if !local[f.Name] {
+				next[f.Name] = cmd.Name
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, sub := range cmd.SubCommands {
+		if err := checkFlagShadowing(sub, currentPath, next); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// validateSubCommandNames checks that no two subcommands share a name. Names are compared the same
+// way they are matched during parsing: ignoring case if fold is true, and exactly otherwise.
+func validateSubCommandNames(cmd *Command, fold bool) error {
+	seen := make(map[string]string, len(cmd.SubCommands)) // name, lowercased if fold -> name
+	for _, sub := range cmd.SubCommands {
+		key := sub.Name
+		if fold {
+			key = strings.ToLower(key)
+		}
+		if other, ok := seen[key]; ok {
+			if other == sub.Name {
+				return fmt.Errorf("duplicate subcommand %q", sub.Name)
+			}
+			return fmt.Errorf("duplicate subcommand %q: conflicts with %q", sub.Name, other)
+		}
+		seen[key] = sub.Name
+	}
+	return nil
+}
+
+// validateHelpFlag checks that a custom help flag alias is a single ASCII letter.
+func validateHelpFlag(cmd *Command) error {
+	if s := cmd.HelpShort; s != "" && !isASCIILetter(s) {
+		return fmt.Errorf("help flag short alias must be a single ASCII letter, got %q", s)
+	}
+	return nil
+}
+
+func isASCIILetter(s string) bool {
+	return len(s) == 1 && (s[0] >= 'a' && s[0] <= 'z' || s[0] >= 'A' && s[0] <= 'Z')
+}
+
 // validateFlagOptions checks that each FlagOption entry refers to a flag that exists in the
-// command's FlagSet, that Short aliases are single ASCII letters, and that no two entries share the
-// same Short alias.
+// command's FlagSet, that Short aliases are single ASCII letters, that no two entries share the
+// same Short alias, and that negatable flags are boolean without a conflicting --no-<name> flag.
 func validateFlagOptions(cmd *Command) error {
 	if len(cmd.FlagOptions) == 0 {
 		return nil
@@ -340,6 +937,17 @@ func validateFlagOptions(cmd *Command) error {
 		if cmd.Flags == nil || cmd.Flags.Lookup(fm.Name) == nil {
 			return fmt.Errorf("flag option references unknown flag %q", fm.Name)
 		}
+		if fm.Local && fm.Persistent {
+			return fmt.Errorf("flag %q: cannot be both local and persistent", fm.Name)
+		}
+		if fm.Negatable {
+			if !isBoolFlag(cmd.Flags.Lookup(fm.Name)) {
+				return fmt.Errorf("flag %q: negatable flags must be boolean", fm.Name)
+			}
+			if cmd.Flags.Lookup(negatedName(fm.Name)) != nil {
+				return fmt.Errorf("flag %q: negated form %q conflicts with an existing flag", fm.Name, negatedName(fm.Name))
+			}
+		}
 		if fm.Short == "" {
 			continue
 		}
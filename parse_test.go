@@ -5,8 +5,10 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"os"
 	"testing"
 
+	"github.com/pressly/cli/flagtype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -190,6 +192,11 @@ func TestParse(t *testing.T) {
 		err := Parse(s.root, []string{"add", "--help"})
 		require.Error(t, err)
 		require.ErrorIs(t, err, flag.ErrHelp)
+		var helpErr *HelpError
+		require.ErrorAs(t, err, &helpErr)
+		require.Equal(t, s.add, helpErr.Command)
+		require.Equal(t, DefaultUsage(s.root), DefaultUsage(helpErr.Command))
+		require.Contains(t, DefaultUsage(helpErr.Command), "todo add")
 	})
 	t.Run("help flag with subcommand at s.root", func(t *testing.T) {
 		t.Parallel()
@@ -547,10 +554,42 @@ func TestParse(t *testing.T) {
 				{Name: "duplicate", Exec: func(ctx context.Context, s *State) error { return nil }},
 			},
 		}
-		// This library may not check for duplicate names, so just verify it works
 		err := Parse(cmd, []string{"duplicate"})
-		require.NoError(t, err)
-		// Just ensure it doesn't crash and can parse the first match
+		require.Error(t, err)
+		require.ErrorContains(t, err, `command ["root"]: duplicate subcommand "duplicate"`)
+	})
+	t.Run("duplicate subcommand names differing in case", func(t *testing.T) {
+		t.Parallel()
+		cmd := &Command{
+			Name: "root",
+			SubCommands: []*Command{{
+				Name: "nested",
+				SubCommands: []*Command{
+					{Name: "list", Exec: func(ctx context.Context, s *State) error { return nil }},
+					{Name: "List", Exec: func(ctx context.Context, s *State) error { return nil }},
+				},
+			}},
+		}
+		err := Parse(cmd, nil)
+		require.Error(t, err)
+		require.ErrorContains(t, err, `command ["root", "nested"]: duplicate subcommand "List": conflicts with "list"`)
+	})
+	t.Run("subcommand names differing in case with case-sensitive matching", func(t *testing.T) {
+		t.Parallel()
+		exec := func(ctx context.Context, s *State) error { return nil }
+		cmd := &Command{
+			Name:      "root",
+			MatchCase: MatchCaseSensitive,
+			SubCommands: []*Command{
+				{Name: "list", Exec: exec},
+				{Name: "List", Exec: exec},
+			},
+		}
+		require.NoError(t, Parse(cmd, []string{"List"}))
+		assert.Equal(t, "List", cmd.terminal().Name)
+		for _, issue := range Validate(cmd) {
+			assert.NotContains(t, issue.Message, "duplicate subcommand")
+		}
 	})
 	t.Run("flag option for non-existent flag", func(t *testing.T) {
 		t.Parallel()
@@ -676,6 +715,25 @@ func TestParse(t *testing.T) {
 		require.Error(t, err, "--force-all should not satisfy required --force")
 		assert.Contains(t, err.Error(), "required flag")
 	})
+	t.Run("negative numbers as positional args", func(t *testing.T) {
+		t.Parallel()
+		add := &Command{
+			Name: "add",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Int("precision", 0, "decimal places")
+			}),
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+		root := &Command{
+			Name:        "calc",
+			SubCommands: []*Command{add},
+		}
+		err := Parse(root, []string{"add", "-5", "3", "--precision", "-1", "-2.5"})
+		require.NoError(t, err)
+		assert.Equal(t, add, getCommand(t, root))
+		assert.Equal(t, -1, GetFlag[int](root.state, "precision"))
+		assert.Equal(t, []string{"-5", "3", "-2.5"}, root.state.Args)
+	})
 	t.Run("mixed flags and args in various orders", func(t *testing.T) {
 		t.Parallel()
 		cmd := &Command{
@@ -717,6 +775,61 @@ func TestShortFlags(t *testing.T) {
 		require.Equal(t, "file.txt", GetFlag[string](cmd.state, "output"))
 	})
 
+	t.Run("short flag with equals syntax", func(t *testing.T) {
+		t.Parallel()
+		child := &Command{
+			Name: "child",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.String("format", "", "output format")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "format", Short: "f"},
+			},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+		root := &Command{
+			Name: "root",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.String("output", "", "output file")
+				f.Bool("verbose", false, "verbose")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "output", Short: "o"},
+				{Name: "verbose", Short: "v"},
+			},
+			SubCommands: []*Command{child},
+			Exec:        func(ctx context.Context, s *State) error { return nil },
+		}
+		err := Parse(root, []string{"-o=file.txt", "-v=true", "child", "-f=json", "arg"})
+		require.NoError(t, err)
+		assert.Equal(t, child, getCommand(t, root))
+		assert.Equal(t, "file.txt", GetFlag[string](root.state, "output"))
+		assert.Equal(t, "json", GetFlag[string](root.state, "format"))
+		assert.True(t, GetFlag[bool](root.state, "verbose"))
+		assert.Equal(t, []string{"arg"}, root.state.Args)
+	})
+
+	t.Run("short flag with equals syntax keeps value verbatim", func(t *testing.T) {
+		t.Parallel()
+		cmd := &Command{
+			Name: "root",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.String("output", "", "output file")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "output", Short: "o"},
+			},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+		err := Parse(cmd, []string{"-o=key=value"})
+		require.NoError(t, err)
+		assert.Equal(t, "key=value", GetFlag[string](cmd.state, "output"))
+
+		err = Parse(cmd, []string{"-o="})
+		require.NoError(t, err)
+		assert.Equal(t, "", GetFlag[string](cmd.state, "output"))
+	})
+
 	t.Run("long flag still works with short alias defined", func(t *testing.T) {
 		t.Parallel()
 		cmd := &Command{
@@ -834,6 +947,461 @@ func TestShortFlags(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), `duplicate short flag "v"`)
 	})
+
+	t.Run("short alias conflict across command path", func(t *testing.T) {
+		t.Parallel()
+		newRoot := func(local bool) *Command {
+			return &Command{
+				Name: "root",
+				Flags: FlagsFunc(func(f *flag.FlagSet) {
+					f.Bool("verbose", false, "enable verbose output")
+				}),
+				FlagOptions: []FlagOption{{Name: "verbose", Short: "v", Local: local}},
+				SubCommands: []*Command{{
+					Name: "child",
+					Flags: FlagsFunc(func(f *flag.FlagSet) {
+						f.Bool("version", false, "show version")
+					}),
+					FlagOptions: []FlagOption{{Name: "version", Short: "v"}},
+					Exec:        func(ctx context.Context, s *State) error { return nil },
+				}},
+				Exec: func(ctx context.Context, s *State) error { return nil },
+			}
+		}
+		err := Parse(newRoot(false), []string{"child", "-v"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `command "root child": short flag "v" used by both "verbose" (from "root") and "version" (from "root child")`)
+
+		// The parent's alias is not visible to the child when the flag is local.
+		root := newRoot(true)
+		require.NoError(t, Parse(root, []string{"child", "-v"}))
+		require.True(t, GetFlag[bool](root.state, "version"))
+
+		// Commands that only see one of the flags are unaffected.
+		root = newRoot(false)
+		require.NoError(t, Parse(root, []string{"-v"}))
+		require.True(t, GetFlag[bool](root.state, "verbose"))
+	})
+}
+
+func TestRepeatedShortFlags(t *testing.T) {
+	t.Parallel()
+
+	newCmd := func() *Command {
+		return &Command{
+			Name: "root",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Var(flagtype.Count(), "verbose", "increase verbosity")
+				f.String("name", "", "the name")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "verbose", Short: "v"},
+				{Name: "name", Short: "n"},
+			},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+	}
+
+	t.Run("stacked short counter", func(t *testing.T) {
+		t.Parallel()
+		cmd := newCmd()
+		err := Parse(cmd, []string{"-vvv", "arg"})
+		require.NoError(t, err)
+		assert.Equal(t, 3, GetFlag[int](cmd.state, "verbose"))
+		assert.Equal(t, []string{"arg"}, cmd.state.Args)
+	})
+	t.Run("mixed forms accumulate", func(t *testing.T) {
+		t.Parallel()
+		cmd := newCmd()
+		err := Parse(cmd, []string{"-v", "arg", "--verbose", "-vv"})
+		require.NoError(t, err)
+		assert.Equal(t, 4, GetFlag[int](cmd.state, "verbose"))
+	})
+	t.Run("unset counter is zero", func(t *testing.T) {
+		t.Parallel()
+		cmd := newCmd()
+		require.NoError(t, Parse(cmd, nil))
+		assert.Equal(t, 0, GetFlag[int](cmd.state, "verbose"))
+	})
+	t.Run("flag value not expanded", func(t *testing.T) {
+		t.Parallel()
+		cmd := newCmd()
+		err := Parse(cmd, []string{"-n", "-vv"})
+		require.NoError(t, err)
+		assert.Equal(t, "-vv", GetFlag[string](cmd.state, "name"))
+		assert.Equal(t, 0, GetFlag[int](cmd.state, "verbose"))
+	})
+	t.Run("stacked non-bool short is unknown", func(t *testing.T) {
+		t.Parallel()
+		cmd := newCmd()
+		err := Parse(cmd, []string{"-nn"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "flag provided but not defined: -nn")
+	})
+	t.Run("usage hides counter type and zero default", func(t *testing.T) {
+		t.Parallel()
+		cmd := newCmd()
+		output := DefaultUsage(cmd)
+		assert.Contains(t, output, "-v, --verbose        increase verbosity")
+		assert.NotContains(t, output, "(default: 0)")
+	})
+}
+
+func TestNegatableFlags(t *testing.T) {
+	t.Parallel()
+
+	newCmd := func() *Command {
+		return &Command{
+			Name: "root",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("color", true, "colorize output")
+				f.Bool("cache", false, "use the cache")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "color", Negatable: true},
+				{Name: "cache", Negatable: true, Required: true},
+			},
+			SubCommands: []*Command{{
+				Name: "child",
+				Exec: func(ctx context.Context, s *State) error { return nil },
+			}},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+	}
+
+	t.Run("negated form sets false", func(t *testing.T) {
+		t.Parallel()
+		cmd := newCmd()
+		err := Parse(cmd, []string{"--no-color", "--cache"})
+		require.NoError(t, err)
+		assert.False(t, GetFlag[bool](cmd.state, "color"))
+		assert.True(t, GetFlag[bool](cmd.state, "cache"))
+	})
+	t.Run("negated form satisfies required and is inherited", func(t *testing.T) {
+		t.Parallel()
+		cmd := newCmd()
+		err := Parse(cmd, []string{"child", "--no-cache"})
+		require.NoError(t, err)
+		assert.False(t, GetFlag[bool](cmd.state, "cache"))
+		assert.True(t, GetFlag[bool](cmd.state, "color"))
+	})
+	t.Run("negated form with explicit value", func(t *testing.T) {
+		t.Parallel()
+		cmd := newCmd()
+		err := Parse(cmd, []string{"--cache", "--no-color=false"})
+		require.NoError(t, err)
+		assert.True(t, GetFlag[bool](cmd.state, "color"))
+	})
+	t.Run("not negatable without option", func(t *testing.T) {
+		t.Parallel()
+		cmd := &Command{
+			Name: "root",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("color", true, "colorize output")
+			}),
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+		err := Parse(cmd, []string{"--no-color"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "flag provided but not defined: -no-color")
+	})
+	t.Run("non-boolean flag rejected", func(t *testing.T) {
+		t.Parallel()
+		cmd := &Command{
+			Name: "root",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.String("output", "", "output file")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "output", Negatable: true},
+			},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+		err := Parse(cmd, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `flag "output": negatable flags must be boolean`)
+	})
+	t.Run("conflicting negated flag rejected", func(t *testing.T) {
+		t.Parallel()
+		cmd := &Command{
+			Name: "root",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("color", true, "colorize output")
+				f.Bool("no-color", false, "disable color")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "color", Negatable: true},
+			},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+		err := Parse(cmd, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `negated form "no-color" conflicts with an existing flag`)
+	})
+	t.Run("usage shows both forms", func(t *testing.T) {
+		t.Parallel()
+		output := DefaultUsage(newCmd())
+		assert.Contains(t, output, "--[no-]color")
+		assert.Contains(t, output, "--[no-]cache")
+		assert.Contains(t, output, "(default: true)")
+	})
+}
+
+func TestFlagsStopAtFirstArg(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func() (*Command, *Command) {
+		run := &Command{
+			Name: "run",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("rm", false, "remove when done")
+				f.String("name", "", "container name")
+			}),
+			FlagsStopAtFirstArg: true,
+			Exec:                func(ctx context.Context, s *State) error { return nil },
+		}
+		root := &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("debug", false, "debug mode")
+			}),
+			SubCommands: []*Command{run},
+			Exec:        func(ctx context.Context, s *State) error { return nil },
+		}
+		return root, run
+	}
+
+	t.Run("flags after first arg passed through", func(t *testing.T) {
+		t.Parallel()
+		root, run := newRoot()
+		err := Parse(root, []string{"--debug", "run", "--rm", "--name", "web", "alpine", "ls", "-la", "--rm"})
+		require.NoError(t, err)
+		assert.Equal(t, run, getCommand(t, root))
+		assert.True(t, GetFlag[bool](root.state, "debug"))
+		assert.True(t, GetFlag[bool](root.state, "rm"))
+		assert.Equal(t, "web", GetFlag[string](root.state, "name"))
+		assert.Equal(t, []string{"alpine", "ls", "-la", "--rm"}, root.state.Args)
+	})
+	t.Run("delimiter after first arg passed through", func(t *testing.T) {
+		t.Parallel()
+		root, _ := newRoot()
+		err := Parse(root, []string{"run", "alpine", "--", "sh", "-c", "true"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alpine", "--", "sh", "-c", "true"}, root.state.Args)
+	})
+	t.Run("delimiter before first arg", func(t *testing.T) {
+		t.Parallel()
+		root, _ := newRoot()
+		err := Parse(root, []string{"run", "--rm", "--", "--not-a-flag"})
+		require.NoError(t, err)
+		assert.True(t, GetFlag[bool](root.state, "rm"))
+		assert.Equal(t, []string{"--not-a-flag"}, root.state.Args)
+	})
+	t.Run("help after first arg passed through", func(t *testing.T) {
+		t.Parallel()
+		root, _ := newRoot()
+		err := Parse(root, []string{"run", "alpine", "--help"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alpine", "--help"}, root.state.Args)
+
+		err = Parse(root, []string{"run", "--help", "alpine"})
+		require.ErrorIs(t, err, ErrHelp)
+	})
+	t.Run("default parses flags anywhere", func(t *testing.T) {
+		t.Parallel()
+		root, run := newRoot()
+		run.FlagsStopAtFirstArg = false
+		err := Parse(root, []string{"run", "alpine", "--rm"})
+		require.NoError(t, err)
+		assert.True(t, GetFlag[bool](root.state, "rm"))
+		assert.Equal(t, []string{"alpine"}, root.state.Args)
+	})
+}
+
+func TestAllowUnknownFlags(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func() *Command {
+		return &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("debug", false, "debug mode")
+			}),
+			SubCommands: []*Command{{
+				Name: "proxy",
+				Flags: FlagsFunc(func(f *flag.FlagSet) {
+					f.String("target", "", "tool to forward to")
+				}),
+				AllowUnknownFlags: true,
+				Exec:              func(ctx context.Context, s *State) error { return nil },
+			}},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+	}
+
+	t.Run("unknown flags kept in order", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		err := Parse(root, []string{"proxy", "--target", "kubectl", "get", "--namespace=prod", "pods", "-o", "wide", "--debug"})
+		require.NoError(t, err)
+		assert.Equal(t, "kubectl", GetFlag[string](root.state, "target"))
+		assert.True(t, GetFlag[bool](root.state, "debug"))
+		assert.Equal(t, []string{"get", "--namespace=prod", "pods", "-o", "wide"}, root.state.Args)
+	})
+	t.Run("args after delimiter appended", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		err := Parse(root, []string{"proxy", "--unknown", "--", "--target"})
+		require.NoError(t, err)
+		assert.Equal(t, "", GetFlag[string](root.state, "target"))
+		assert.Equal(t, []string{"--unknown", "--target"}, root.state.Args)
+	})
+	t.Run("unknown flag before command name", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		err := Parse(root, []string{"--foo", "proxy", "x", "proxy"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"--foo", "x", "proxy"}, root.state.Args)
+	})
+	t.Run("invalid known flag still errors", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		err := Parse(root, []string{"proxy", "--debug=maybe", "--unknown"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid boolean value")
+	})
+	t.Run("not allowed by default", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		err := Parse(root, []string{"--unknown"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "flag provided but not defined: -unknown")
+	})
+}
+
+func TestHelpFlag(t *testing.T) {
+	t.Parallel()
+
+	exec := func(ctx context.Context, s *State) error { return nil }
+
+	t.Run("all forms request help", func(t *testing.T) {
+		t.Parallel()
+		for _, arg := range []string{"-h", "--h", "-help", "--help"} {
+			root := &Command{Name: "app", Exec: exec}
+			require.ErrorIs(t, Parse(root, []string{arg}), ErrHelp, arg)
+		}
+	})
+	t.Run("renamed", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{Name: "app", HelpFlag: "usage", HelpShort: "u", Exec: exec}
+		require.ErrorIs(t, Parse(root, []string{"--usage"}), ErrHelp)
+		require.ErrorIs(t, Parse(root, []string{"-u"}), ErrHelp)
+		err := Parse(root, []string{"--help"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "flag provided but not defined: -help")
+	})
+	t.Run("short alias taken by a flag", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.String("host", "", "server host")
+			}),
+			FlagOptions: []FlagOption{{Name: "host", Short: "h"}},
+			SubCommands: []*Command{{Name: "ping", Exec: exec}},
+			Exec:        exec,
+		}
+		require.NoError(t, Parse(root, []string{"ping", "-h", "example.com"}))
+		assert.Equal(t, "example.com", GetFlag[string](root.state, "host"))
+		require.ErrorIs(t, Parse(root, []string{"ping", "--help"}), ErrHelp)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{Name: "app", DisableHelpFlag: true, Exec: exec}
+		err := Parse(root, []string{"--help"})
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrHelp)
+	})
+	t.Run("invalid short alias", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{Name: "app", HelpShort: "hh", Exec: exec}
+		err := Parse(root, []string{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `help flag short alias must be a single ASCII letter, got "hh"`)
+	})
+}
+
+func TestEnvFlags(t *testing.T) {
+	newRoot := func() *Command {
+		return &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Int("port", 8080, "listen port")
+				f.String("token", "", "API token")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "port", EnvVar: "CLI_TEST_PORT"},
+				{Name: "token", EnvVar: "CLI_TEST_TOKEN", Required: true},
+			},
+			SubCommands: []*Command{{
+				Name: "serve",
+				Exec: func(ctx context.Context, s *State) error { return nil },
+			}},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+	}
+
+	t.Setenv("CLI_TEST_PORT", "9090")
+	t.Setenv("CLI_TEST_TOKEN", "secret")
+
+	t.Run("value from environment", func(t *testing.T) {
+		root := newRoot()
+		require.NoError(t, Parse(root, []string{"serve"}))
+		assert.Equal(t, 9090, GetFlag[int](root.state, "port"))
+		assert.Equal(t, "secret", GetFlag[string](root.state, "token"))
+	})
+	t.Run("command line wins", func(t *testing.T) {
+		root := newRoot()
+		require.NoError(t, Parse(root, []string{"serve", "--port=1234"}))
+		assert.Equal(t, 1234, GetFlag[int](root.state, "port"))
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		t.Setenv("CLI_TEST_PORT", "http")
+		err := Parse(newRoot(), []string{"serve"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid value "http" for flag -port from environment variable CLI_TEST_PORT`)
+	})
+	t.Run("redefined by child", func(t *testing.T) {
+		t.Setenv("CLI_TEST_SERVE_PORT", "7070")
+		newChild := func(opts ...FlagOption) *Command {
+			return &Command{
+				Name: "serve",
+				Flags: FlagsFunc(func(f *flag.FlagSet) {
+					f.Int("port", 3000, "serve port")
+				}),
+				FlagOptions: opts,
+				Exec:        func(ctx context.Context, s *State) error { return nil },
+			}
+		}
+		// The child's own variable wins over the ancestor's.
+		root := newRoot()
+		root.SubCommands = []*Command{newChild(FlagOption{Name: "port", EnvVar: "CLI_TEST_SERVE_PORT"})}
+		require.NoError(t, Parse(root, []string{"serve"}))
+		assert.Equal(t, 7070, GetFlag[int](root.state, "port"))
+		assert.Equal(t, 8080, GetFlag[int](&State{path: root.state.path[:1]}, "port"))
+		// Without its own variable, the child's flag is not set from the ancestor's.
+		root = newRoot()
+		root.SubCommands = []*Command{newChild()}
+		require.NoError(t, Parse(root, []string{"serve"}))
+		assert.Equal(t, 3000, GetFlag[int](root.state, "port"))
+	})
+	t.Run("unset variable", func(t *testing.T) {
+		require.NoError(t, os.Unsetenv("CLI_TEST_TOKEN"))
+		root := newRoot()
+		err := Parse(root, []string{"serve"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `required flag "-token" not set`)
+	})
 }
 
 func TestLocalFlags(t *testing.T) {
@@ -992,6 +1560,53 @@ func TestLocalFlags(t *testing.T) {
 		require.Error(t, err)
 		require.ErrorContains(t, err, "flag provided but not defined")
 	})
+
+	t.Run("flags local by default", func(t *testing.T) {
+		t.Parallel()
+		newRoot := func() *Command {
+			return &Command{
+				Name: "root",
+				Flags: FlagsFunc(func(f *flag.FlagSet) {
+					f.Bool("version", false, "show version")
+					f.Bool("verbose", false, "enable verbose output")
+				}),
+				FlagOptions:         []FlagOption{{Name: "verbose", Persistent: true}},
+				FlagsLocalByDefault: true,
+				SubCommands: []*Command{{
+					Name: "child",
+					Exec: func(ctx context.Context, s *State) error { return nil },
+				}},
+				Exec: func(ctx context.Context, s *State) error { return nil },
+			}
+		}
+		root := newRoot()
+		require.NoError(t, Parse(root, []string{"child", "--verbose"}))
+		assert.True(t, GetFlag[bool](root.state, "verbose"))
+		usage := DefaultUsage(root)
+		assert.Contains(t, usage, "Inherited Flags:")
+		assert.NotContains(t, usage, "--version")
+
+		err := Parse(newRoot(), []string{"child", "--version"})
+		require.ErrorContains(t, err, "flag provided but not defined: -version")
+
+		root = newRoot()
+		require.NoError(t, Parse(root, []string{"--version"}))
+		assert.True(t, GetFlag[bool](root.state, "version"))
+	})
+
+	t.Run("local and persistent conflict", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{
+			Name: "root",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("verbose", false, "enable verbose output")
+			}),
+			FlagOptions: []FlagOption{{Name: "verbose", Local: true, Persistent: true}},
+			Exec:        func(ctx context.Context, s *State) error { return nil },
+		}
+		err := Parse(root, nil)
+		require.ErrorContains(t, err, `flag "verbose": cannot be both local and persistent`)
+	})
 }
 
 func getCommand(t *testing.T, c *Command) *Command {
@@ -1002,3 +1617,364 @@ func getCommand(t *testing.T, c *Command) *Command {
 	require.NotNil(t, terminal)
 	return terminal
 }
+
+func TestFlagShadowing(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func(strict, local bool) *Command {
+		return &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.String("output", "", "output file")
+			}),
+			FlagOptions:           []FlagOption{{Name: "output", Local: local}},
+			DisallowFlagShadowing: strict,
+			SubCommands: []*Command{{
+				Name: "report",
+				SubCommands: []*Command{{
+					Name: "export",
+					Flags: FlagsFunc(func(f *flag.FlagSet) {
+						f.String("output", "json", "output format")
+					}),
+					Exec: func(ctx context.Context, s *State) error { return nil },
+				}},
+			}},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+	}
+
+	t.Run("child wins by default", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot(false, false)
+		require.NoError(t, Parse(root, []string{"report", "export", "--output=yaml"}))
+		assert.Equal(t, "yaml", GetFlag[string](root.state, "output"))
+	})
+	t.Run("strict", func(t *testing.T) {
+		t.Parallel()
+		// The whole tree is checked, not just the resolved path.
+		err := Parse(newRoot(true, false), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `command ["app", "report", "export"]: flag "output" shadows the flag inherited from "app"`)
+	})
+	t.Run("strict with local ancestor flag", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, Parse(newRoot(true, true), []string{"report", "export"}))
+	})
+}
+
+func TestPreParse(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func(preParse func([]string) ([]string, error)) *Command {
+		return &Command{
+			Name:     "git",
+			PreParse: preParse,
+			SubCommands: []*Command{{
+				Name: "checkout",
+				Flags: FlagsFunc(func(f *flag.FlagSet) {
+					f.Bool("force", false, "discard local changes")
+				}),
+				Exec: func(ctx context.Context, s *State) error { return nil },
+			}},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+	}
+
+	t.Run("rewrites arguments", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot(func(args []string) ([]string, error) {
+			if len(args) > 0 && args[0] == "co" {
+				return append([]string{"checkout", "--force"}, args[1:]...), nil
+			}
+			return args, nil
+		})
+		require.NoError(t, Parse(root, []string{"co", "main"}))
+		assert.Equal(t, "checkout", root.state.Command().Name)
+		assert.True(t, GetFlag[bool](root.state, "force"))
+		assert.Equal(t, []string{"main"}, root.state.Args)
+	})
+	t.Run("does not modify caller args", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot(func(args []string) ([]string, error) {
+			args[0] = "checkout"
+			return args, nil
+		})
+		args := []string{"co"}
+		require.NoError(t, Parse(root, args))
+		assert.Equal(t, []string{"co"}, args)
+	})
+	t.Run("error stops parsing", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot(func(args []string) ([]string, error) {
+			return nil, errors.New("alias loop")
+		})
+		err := Parse(root, []string{"co"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse: alias loop")
+	})
+}
+
+func TestJoinedParseErrors(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func() *Command {
+		return &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Int("port", 8080, "listen port")
+				f.Int("workers", 1, "number of workers")
+				f.String("name", "", "service name")
+				f.String("region", "", "cloud region")
+				f.Bool("verbose", false, "verbose output")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "port", Required: true},
+				{Name: "workers", EnvVar: "APP_WORKERS"},
+				{Name: "name", Short: "n", Required: true},
+				{Name: "region", Required: true},
+			},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+	}
+
+	t.Run("all errors reported", func(t *testing.T) {
+		t.Parallel()
+		err := ParseAndRun(context.Background(), newRoot(), []string{"--port=http", "--verbose=maybe"}, &RunOptions{
+			LookupEnv: func(key string) (string, bool) {
+				if key == "APP_WORKERS" {
+					return "many", true
+				}
+				return "", false
+			},
+		})
+		require.Error(t, err)
+		want := `command "app": invalid value "http" for flag -port: parse error
+command "app": invalid boolean value "maybe" for -verbose: parse error
+command "app": invalid value "many" for flag -workers from environment variable APP_WORKERS: parse error
+command "app": required flags "-name, -region" not set`
+		assert.Equal(t, want, err.Error())
+	})
+	t.Run("required flag set by short alias", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		err := Parse(root, []string{"-n", "api", "--port=80", "--region=eu"})
+		require.NoError(t, err)
+		assert.Equal(t, "api", GetFlag[string](root.state, "name"))
+	})
+	t.Run("missing value not reported as missing flag", func(t *testing.T) {
+		t.Parallel()
+		err := Parse(newRoot(), []string{"--port=80", "--region=eu", "--name"})
+		require.Error(t, err)
+		assert.Equal(t, `command "app": flag needs an argument: -name`, err.Error())
+	})
+}
+
+func TestUsageError(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func() *Command {
+		return &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Int("port", 8080, "listen port")
+				f.String("name", "", "service name")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "name", Required: true},
+			},
+			SubCommands: []*Command{{
+				Name: "get",
+				Exec: func(ctx context.Context, s *State) error {
+					_, err := s.ArgInt(0)
+					return err
+				},
+			}},
+		}
+	}
+
+	for _, args := range [][]string{
+		{"gte"},
+		{"get", "--name=x", "--unknown"},
+		{"get", "--name=x", "--port=http"},
+		{"get"},
+	} {
+		err := Parse(newRoot(), args)
+		require.Error(t, err, args)
+		assert.ErrorIs(t, err, ErrUsage, args)
+		var usageErr *UsageError
+		require.ErrorAs(t, err, &usageErr, args)
+		assert.Equal(t, 2, usageErr.ExitCode())
+	}
+	t.Run("missing argument", func(t *testing.T) {
+		t.Parallel()
+		err := ParseAndRun(context.Background(), newRoot(), []string{"get", "--name=x"}, nil)
+		require.ErrorIs(t, err, ErrUsage)
+		assert.Equal(t, "missing argument 1", err.Error())
+	})
+	t.Run("not a usage error", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		root.Flags = nil
+		root.FlagOptions = []FlagOption{{Name: "name", Required: true}}
+		err := Parse(root, nil)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrUsage)
+	})
+}
+
+func TestSuggestDepth(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func(depth int) *Command {
+		exec := func(ctx context.Context, s *State) error { return nil }
+		return &Command{
+			Name:         "todo",
+			SuggestDepth: depth,
+			SubCommands: []*Command{
+				{Name: "add", Exec: exec},
+				{Name: "list", Exec: exec, SubCommands: []*Command{
+					{Name: "overdue", Exec: exec},
+					{Name: "done", Exec: exec, SubCommands: []*Command{
+						{Name: "today", Exec: exec},
+					}},
+				}},
+			},
+			Exec: exec,
+		}
+	}
+
+	t.Run("direct subcommands by default", func(t *testing.T) {
+		t.Parallel()
+		err := Parse(newRoot(0), []string{"overdue"})
+		require.Error(t, err)
+		assert.Equal(t, `unknown command "overdue"`, err.Error())
+	})
+	t.Run("nested", func(t *testing.T) {
+		t.Parallel()
+		err := Parse(newRoot(2), []string{"overdue"})
+		require.Error(t, err)
+		assert.Equal(t, "unknown command \"overdue\". Did you mean one of these?\n\tlist overdue", err.Error())
+
+		err = Parse(newRoot(2), []string{"today"})
+		require.Error(t, err)
+		assert.Equal(t, `unknown command "today"`, err.Error())
+	})
+	t.Run("deeper", func(t *testing.T) {
+		t.Parallel()
+		err := Parse(newRoot(3), []string{"todya"})
+		require.Error(t, err)
+		assert.Equal(t, "unknown command \"todya\". Did you mean one of these?\n\tlist done today", err.Error())
+
+		err = Parse(newRoot(3), []string{"list", "tody"})
+		require.Error(t, err)
+		assert.Equal(t, "unknown command \"tody\". Did you mean one of these?\n\tdone today", err.Error())
+	})
+}
+
+func TestPrefixMatching(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func(enabled bool) (*Command, map[string]*Command) {
+		exec := func(ctx context.Context, s *State) error { return nil }
+		cmds := map[string]*Command{
+			"list":   {Name: "list", Exec: exec},
+			"listen": {Name: "listen", Exec: exec},
+			"login":  {Name: "login", Exec: exec},
+			"add":    {Name: "add", Exec: exec},
+		}
+		return &Command{
+			Name:           "todo",
+			PrefixMatching: enabled,
+			SubCommands:    []*Command{cmds["list"], cmds["listen"], cmds["login"], cmds["add"]},
+			Exec:           exec,
+		}, cmds
+	}
+
+	t.Run("unique prefix", func(t *testing.T) {
+		t.Parallel()
+		root, cmds := newRoot(true)
+		require.NoError(t, Parse(root, []string{"a", "milk"}))
+		assert.Equal(t, cmds["add"], root.terminal())
+		assert.Equal(t, []string{"milk"}, root.state.Args)
+
+		require.NoError(t, Parse(root, []string{"LOG"}))
+		assert.Equal(t, cmds["login"], root.terminal())
+	})
+	t.Run("exact name wins", func(t *testing.T) {
+		t.Parallel()
+		root, cmds := newRoot(true)
+		require.NoError(t, Parse(root, []string{"list"}))
+		assert.Equal(t, cmds["list"], root.terminal())
+	})
+	t.Run("ambiguous", func(t *testing.T) {
+		t.Parallel()
+		root, _ := newRoot(true)
+		err := Parse(root, []string{"l"})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrUsage)
+		assert.Equal(t, "ambiguous command \"l\". It matches all of these:\n\tlist\n\tlisten\n\tlogin", err.Error())
+	})
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+		root, _ := newRoot(false)
+		err := Parse(root, []string{"a"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown command "a"`)
+	})
+}
+
+func TestMatchCase(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func(m MatchCase) *Command {
+		return &Command{
+			Name:      "todo",
+			MatchCase: m,
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("verbose", false, "verbose output")
+				f.Bool("V", false, "show version")
+				f.Bool("v", false, "short verbose")
+				f.String("output", "", "output file")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "output", Short: "o"},
+			},
+			SubCommands: []*Command{{
+				Name: "list",
+				Exec: func(ctx context.Context, s *State) error { return nil },
+			}},
+		}
+	}
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot(MatchCaseDefault)
+		require.NoError(t, Parse(root, []string{"LIST"}))
+		assert.Empty(t, root.state.Args)
+
+		err := Parse(newRoot(MatchCaseDefault), []string{"list", "--Verbose"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "flag provided but not defined: -Verbose")
+	})
+	t.Run("sensitive", func(t *testing.T) {
+		t.Parallel()
+		err := Parse(newRoot(MatchCaseSensitive), []string{"LIST"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown command "LIST"`)
+		require.NoError(t, Parse(newRoot(MatchCaseSensitive), []string{"list"}))
+	})
+	t.Run("insensitive", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot(MatchCaseInsensitive)
+		require.NoError(t, Parse(root, []string{"-Verbose", "--OUTPUT", "x", "List", "--Output=b.txt", "-O", "A.txt", "arg"}))
+		assert.Equal(t, []string{"arg"}, root.state.Args)
+		assert.True(t, GetFlag[bool](root.state, "verbose"))
+		assert.Equal(t, "A.txt", GetFlag[string](root.state, "output"))
+
+		// Exact matches win over names that differ only in case.
+		root = newRoot(MatchCaseInsensitive)
+		require.NoError(t, Parse(root, []string{"list", "-V"}))
+		assert.True(t, GetFlag[bool](root.state, "V"))
+		assert.False(t, GetFlag[bool](root.state, "v"))
+	})
+}
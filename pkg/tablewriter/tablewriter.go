@@ -0,0 +1,175 @@
+// Package tablewriter renders rows of text as aligned columns, suitable for commands that list
+// resources. Columns are padded to the widest cell, and when the table is wider than the maximum
+// width the widest columns are shrunk and their cells truncated with an ellipsis.
+//
+// Example:
+//
+//	t := tablewriter.New(s.Stdout)
+//	t.SetHeader("ID", "STATUS", "TEXT")
+//	for _, task := range tasks {
+//	    t.Append(strconv.Itoa(task.ID), string(task.Status), task.Text)
+//	}
+//	if err := t.Render(); err != nil {
+//	    return err
+//	}
+package tablewriter
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// defaultWidth is the table width used when the terminal width cannot be determined.
+	defaultWidth = 80
+	// defaultPadding is the number of spaces between columns.
+	defaultPadding = 2
+	// minColumnWidth is the narrowest a column is shrunk to when fitting the table to the maximum
+	// width. Tables with many columns may still exceed the maximum width.
+	minColumnWidth = 4
+	ellipsis       = "..."
+)
+
+// Table accumulates a header and rows and renders them as aligned columns. The zero value is not
+// usable, use [New] to create a Table.
+type Table struct {
+	w        io.Writer
+	header   []string
+	rows     [][]string
+	maxWidth int
+	padding  int
+}
+
+// New returns a Table that renders to w. The maximum width defaults to [TerminalWidth].
+func New(w io.Writer) *Table {
+	return &Table{
+		w:        w,
+		maxWidth: TerminalWidth(),
+		padding:  defaultPadding,
+	}
+}
+
+// SetHeader sets the column headers, rendered as the first row.
+func (t *Table) SetHeader(columns ...string) {
+	t.header = columns
+}
+
+// Append adds a row. Rows may have fewer cells than the header (missing cells are rendered empty)
+// or more (extra columns are added).
+func (t *Table) Append(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// SetMaxWidth sets the maximum rendered line width. A zero or negative width disables truncation.
+func (t *Table) SetMaxWidth(width int) {
+	t.maxWidth = width
+}
+
+// SetPadding sets the number of spaces between columns. Defaults to 2.
+func (t *Table) SetPadding(padding int) {
+	if padding < 0 {
+		padding = 0
+	}
+	t.padding = padding
+}
+
+// Render writes the table to the underlying writer. Trailing whitespace is not written, so the last
+// column is never padded.
+func (t *Table) Render() error {
+	all := t.rows
+	if len(t.header) > 0 {
+		all = append([][]string{t.header}, t.rows...)
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	widths := columnWidths(all)
+	if t.maxWidth > 0 {
+		shrink(widths, t.maxWidth, t.padding)
+	}
+
+	var b strings.Builder
+	gap := strings.Repeat(" ", t.padding)
+	for _, row := range all {
+		var line strings.Builder
+		for i, width := range widths {
+			var cell string
+			if i < len(row) {
+				cell = truncate(row[i], width)
+			}
+			if i > 0 {
+				line.WriteString(gap)
+			}
+			line.WriteString(cell)
+			if i < len(widths)-1 {
+				line.WriteString(strings.Repeat(" ", width-utf8.RuneCountInString(cell)))
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(t.w, b.String())
+	return err
+}
+
+// TerminalWidth returns the width of the terminal as reported by the COLUMNS environment variable,
+// or 80 if it is unset or invalid.
+func TerminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultWidth
+}
+
+func columnWidths(rows [][]string) []int {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	return widths
+}
+
+// shrink narrows the widest columns, one character at a time, until the total line width fits
+// within maxWidth or every column has reached minColumnWidth.
+func shrink(widths []int, maxWidth, padding int) {
+	total := padding * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for total > maxWidth {
+		widest := 0
+		for i := range widths {
+			if widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// truncate shortens s to at most width runes, replacing the tail with an ellipsis when there is
+// room for one.
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	if width <= len(ellipsis) {
+		return string(runes[:width])
+	}
+	return string(runes[:width-len(ellipsis)]) + ellipsis
+}
@@ -0,0 +1,106 @@
+package tablewriter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   []string
+		rows     [][]string
+		maxWidth int
+		expected string
+	}{
+		{
+			name:     "empty table",
+			expected: "",
+		},
+		{
+			name:   "header and rows aligned",
+			header: []string{"ID", "STATUS", "TEXT"},
+			rows: [][]string{
+				{"1", "pending", "buy milk"},
+				{"12", "done", "walk the dog"},
+			},
+			expected: "" +
+				"ID  STATUS   TEXT\n" +
+				"1   pending  buy milk\n" +
+				"12  done     walk the dog\n",
+		},
+		{
+			name: "rows without header",
+			rows: [][]string{
+				{"a", "b"},
+				{"ccc", "d"},
+			},
+			expected: "" +
+				"a    b\n" +
+				"ccc  d\n",
+		},
+		{
+			name:   "ragged rows",
+			header: []string{"NAME", "VALUE"},
+			rows: [][]string{
+				{"only-name"},
+				{"x", "y", "extra"},
+			},
+			expected: "" +
+				"NAME       VALUE\n" +
+				"only-name\n" +
+				"x          y      extra\n",
+		},
+		{
+			name:   "widest column truncated to fit",
+			header: []string{"ID", "TEXT"},
+			rows: [][]string{
+				{"1", "this text is far too long to fit"},
+			},
+			maxWidth: 20,
+			expected: "" +
+				"ID  TEXT\n" +
+				"1   this text is ...\n",
+		},
+		{
+			name:   "multibyte runes counted as single columns",
+			header: []string{"NAME", "CITY"},
+			rows: [][]string{
+				{"zoë", "münchen"},
+			},
+			expected: "" +
+				"NAME  CITY\n" +
+				"zoë   münchen\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			table := New(&buf)
+			table.SetMaxWidth(tt.maxWidth)
+			table.SetHeader(tt.header...)
+			for _, row := range tt.rows {
+				table.Append(row...)
+			}
+			require.NoError(t, table.Render())
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "hello", truncate("hello", 5))
+	assert.Equal(t, "he...", truncate("hello world", 5))
+	assert.Equal(t, "hel", truncate("hello", 3))
+}
+
+func TestTerminalWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "120")
+	assert.Equal(t, 120, TerminalWidth())
+	t.Setenv("COLUMNS", "not-a-number")
+	assert.Equal(t, defaultWidth, TerminalWidth())
+}
@@ -0,0 +1,125 @@
+package textutil
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RenderMarkdown renders lightweight markdown as plain text for a terminal, wrapped to width
+// columns. It supports the subset that reads well both in help output and in generated
+// documentation:
+//
+//   - paragraphs, separated by blank lines, which are re-wrapped
+//   - bullet lists, with items starting with "-", "*", or "+", rendered as "- " with a hanging
+//     indent
+//   - **bold** and `code` spans, rendered as their text
+//   - headings, starting with "#", rendered as their text
+//   - code blocks, fenced with ``` or indented by four spaces, which are kept verbatim; fenced
+//     blocks are indented by two spaces
+//
+// Anything else is treated as paragraph text.
+func RenderMarkdown(text string, width int) string {
+	type block struct {
+		text string
+		item bool
+	}
+	const (
+		none = iota
+		paragraph
+		item
+		code
+	)
+	var (
+		blocks  []block
+		buf     []string
+		kind    = none
+		inFence bool
+	)
+	flush := func() {
+		switch kind {
+		case paragraph:
+			blocks = append(blocks, block{text: strings.Join(Wrap(stripInline(strings.Join(buf, " ")), width), "\n")})
+		case item:
+			blocks = append(blocks, block{text: "- " + WrapHanging(stripInline(strings.Join(buf, " ")), width-2, 2), item: true})
+		case code:
+			if len(buf) > 0 {
+				blocks = append(blocks, block{text: strings.Join(buf, "\n")})
+			}
+		}
+		buf, kind = nil, none
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			flush()
+			if inFence = !inFence; inFence {
+				kind = code
+			}
+			continue
+		}
+		if inFence {
+			if line == "" {
+				buf = append(buf, "")
+			} else {
+				buf = append(buf, "  "+line)
+			}
+			continue
+		}
+		switch {
+		case trimmed == "":
+			flush()
+		case listItemRegex.MatchString(trimmed):
+			flush()
+			kind = item
+			buf = append(buf, strings.TrimSpace(trimmed[1:]))
+		case kind == item:
+			buf = append(buf, trimmed)
+		case strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t"):
+			if kind != code {
+				flush()
+				kind = code
+			}
+			buf = append(buf, line)
+		case headingRegex.MatchString(trimmed):
+			flush()
+			blocks = append(blocks, block{text: stripInline(headingRegex.ReplaceAllString(trimmed, ""))})
+		default:
+			if kind != paragraph {
+				flush()
+				kind = paragraph
+			}
+			buf = append(buf, trimmed)
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for i, blk := range blocks {
+		if i > 0 {
+			if blk.item && blocks[i-1].item {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(blk.text)
+	}
+	return b.String()
+}
+
+var (
+	listItemRegex = regexp.MustCompile(`^[-*+]\s`)
+	headingRegex  = regexp.MustCompile(`^#{1,6}\s+`)
+	inlineRegex   = regexp.MustCompile("`([^`]+)`|\\*\\*(.+?)\\*\\*|__(.+?)__")
+)
+
+// stripInline replaces code and bold spans with their text.
+func stripInline(s string) string {
+	return inlineRegex.ReplaceAllStringFunc(s, func(m string) string {
+		if strings.HasPrefix(m, "`") {
+			return m[1 : len(m)-1]
+		}
+		return m[2 : len(m)-2]
+	})
+}
@@ -0,0 +1,46 @@
+package textutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	text := `# Overview
+
+Sync copies **new and changed** files from the source to the
+destination. Use ` + "`--dry-run`" + ` to preview.
+
+Modes:
+- ` + "`mirror`" + ` deletes files that are missing from the source, so the destination is an exact copy
+- ` + "`update`" + ` only adds
+  and changes files
+
+    app sync ./src ./dst
+
+` + "```" + `
+app sync --mode=mirror a b
+` + "```"
+	want := `Overview
+
+Sync copies new and changed files from the source to the
+destination. Use --dry-run to preview.
+
+Modes:
+
+- mirror deletes files that are missing from the source, so
+  the destination is an exact copy
+- update only adds and changes files
+
+    app sync ./src ./dst
+
+  app sync --mode=mirror a b`
+	assert.Equal(t, want, RenderMarkdown(text, 60))
+}
+
+func TestRenderMarkdownPlainText(t *testing.T) {
+	assert.Equal(t, "just some text", RenderMarkdown("just some text", 80))
+	assert.Equal(t, "", RenderMarkdown("", 80))
+	assert.Equal(t, "*.go files and 2 * 3", RenderMarkdown("*.go files and 2 * 3", 80))
+}
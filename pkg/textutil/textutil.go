@@ -1,7 +1,18 @@
+// Package textutil provides helpers for laying out text in a terminal, such as wrapping help text
+// to a width. Widths are measured in terminal columns: ANSI escape sequences, like color codes,
+// take no space, and wide runes, like CJK characters, take two columns.
 package textutil
 
-import "strings"
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
 
+// Wrap splits text into lines of at most width columns, breaking at spaces. Runs of whitespace,
+// including newlines, are collapsed into a single space. A word longer than width is put on a line
+// of its own rather than broken. ANSI escape sequences are kept with the word they are attached
+// to and do not count towards the width.
 func Wrap(text string, width int) []string {
 	words := strings.Fields(text)
 	var (
@@ -10,20 +21,21 @@ func Wrap(text string, width int) []string {
 		currentLength int
 	)
 	for _, word := range words {
-		if currentLength+len(word)+1 > width {
+		wordLength := Width(word)
+		if currentLength+wordLength+1 > width {
 			if len(currentLine) > 0 {
 				lines = append(lines, strings.Join(currentLine, " "))
 				currentLine = []string{word}
-				currentLength = len(word)
+				currentLength = wordLength
 			} else {
 				lines = append(lines, word)
 			}
 		} else {
 			currentLine = append(currentLine, word)
 			if currentLength == 0 {
-				currentLength = len(word)
+				currentLength = wordLength
 			} else {
-				currentLength += len(word) + 1
+				currentLength += wordLength + 1
 			}
 		}
 	}
@@ -32,3 +44,93 @@ func Wrap(text string, width int) []string {
 	}
 	return lines
 }
+
+// WrapHanging wraps text like [Wrap] and joins the lines into one string with every line after the
+// first indented by indent spaces. This is the hanging indent of two-column layouts, where the
+// first line continues after a label and the rest align under it:
+//
+//	fmt.Fprintf(w, "  %-12s%s\n", "--output", textutil.WrapHanging(usage, 66, 14))
+func WrapHanging(text string, width, indent int) string {
+	return strings.Join(Wrap(text, width), "\n"+strings.Repeat(" ", indent))
+}
+
+// Width returns the number of terminal columns s takes up. ANSI escape sequences and zero-width
+// runes, like combining marks, count as zero columns, and East Asian wide and fullwidth runes, like
+// CJK characters, count as two.
+func Width(s string) int {
+	var n int
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			i += escapeLen(s[i:])
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		n += runeWidth(r)
+		i += size
+	}
+	return n
+}
+
+// escapeLen returns the length of the ANSI escape sequence at the start of s, which starts with
+// ESC. It recognizes CSI sequences, like the SGR color codes "\x1b[1;31m", and OSC sequences, like
+// hyperlinks, terminated by BEL or ST. Any other escape is treated as ESC and the byte after it.
+func escapeLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		// CSI: parameter and intermediate bytes, then a final byte in the range 0x40-0x7E.
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+// wideRanges are the ranges of East Asian wide and fullwidth runes, and emoji, that take two
+// columns in a terminal.
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115f},   // Hangul Jamo initial consonants
+	{0x2e80, 0x303e},   // CJK radicals, Kangxi radicals, CJK symbols and punctuation
+	{0x3041, 0x33ff},   // Hiragana, Katakana, Bopomofo, Hangul compatibility Jamo, CJK compatibility
+	{0x3400, 0x4dbf},   // CJK unified ideographs extension A
+	{0x4e00, 0x9fff},   // CJK unified ideographs
+	{0xa000, 0xa4cf},   // Yi syllables and radicals
+	{0xac00, 0xd7a3},   // Hangul syllables
+	{0xf900, 0xfaff},   // CJK compatibility ideographs
+	{0xfe30, 0xfe4f},   // CJK compatibility forms
+	{0xff00, 0xff60},   // Fullwidth forms
+	{0xffe0, 0xffe6},   // Fullwidth signs
+	{0x1f300, 0x1f64f}, // Miscellaneous symbols and pictographs, emoticons
+	{0x1f900, 0x1f9ff}, // Supplemental symbols and pictographs
+	{0x20000, 0x3fffd}, // CJK unified ideographs extensions B and later
+}
+
+func runeWidth(r rune) int {
+	if unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r) {
+		return 0
+	}
+	for _, w := range wideRanges {
+		if r < w.lo {
+			break
+		}
+		if r <= w.hi {
+			return 2
+		}
+	}
+	return 1
+}
@@ -49,6 +49,18 @@ func TestWrapText(t *testing.T) {
 			width:    20,
 			expected: []string{"hello world"},
 		},
+		{
+			name:     "ansi escape sequences",
+			text:     "\x1b[1mbold\x1b[0m and \x1b[31mred\x1b[0m text",
+			width:    12,
+			expected: []string{"\x1b[1mbold\x1b[0m and \x1b[31mred\x1b[0m", "text"},
+		},
+		{
+			name:     "wide runes",
+			text:     "日本語 の テキスト です",
+			width:    10,
+			expected: []string{"日本語 の", "テキスト", "です"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -58,3 +70,28 @@ func TestWrapText(t *testing.T) {
 		})
 	}
 }
+
+func TestWrapHanging(t *testing.T) {
+	assert.Equal(t, "this is a\n    long text\n    that needs\n    wrapping", WrapHanging("this is a long text that needs wrapping", 10, 4))
+	assert.Equal(t, "short", WrapHanging("short", 10, 4))
+	assert.Equal(t, "", WrapHanging("", 10, 4))
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"\x1b[1;31mred\x1b[0m", 3},
+		{"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", 4},
+		{"日本語", 6},
+		{"ｆｕｌｌ", 8},
+		{"e\u0301", 1},
+		{"naïve", 5},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.width, Width(tt.text), "width of %q", tt.text)
+	}
+}
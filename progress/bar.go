@@ -0,0 +1,137 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Bar is a determinate progress indicator that renders completion as a filled bar and percentage.
+// It is safe for concurrent use, so workers may call [Bar.Add] from multiple goroutines.
+type Bar struct {
+	w     io.Writer
+	cfg   config
+	total int64
+
+	mu       sync.Mutex
+	current  int64
+	msg      string
+	started  bool
+	finished bool
+	lastPct  int
+	stop     chan struct{}
+}
+
+// NewBar returns a Bar that writes to w and is complete when total units of work are done. The bar
+// does not render until [Bar.Start] is called.
+func NewBar(w io.Writer, total int64, opts ...Option) *Bar {
+	if total < 0 {
+		total = 0
+	}
+	return &Bar{
+		w:       w,
+		cfg:     newConfig(w, opts),
+		total:   total,
+		lastPct: -1,
+	}
+}
+
+// Start renders the empty bar. If ctx is canceled before [Bar.Finish] is called, the bar stops
+// rendering and further updates are ignored. Calling Start more than once is a no-op.
+func (b *Bar) Start(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.started || !*b.cfg.enabled {
+		return
+	}
+	b.started = true
+	b.stop = make(chan struct{})
+	b.render()
+	go func(stop <-chan struct{}) {
+		select {
+		case <-ctx.Done():
+			b.Finish()
+		case <-stop:
+		}
+	}(b.stop)
+}
+
+// Add advances the bar by n units of work.
+func (b *Bar) Add(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.set(b.current + n)
+}
+
+// Set moves the bar to n units of work. Values are clamped to the range [0, total].
+func (b *Bar) Set(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.set(n)
+}
+
+// SetMessage sets a message displayed after the percentage.
+func (b *Bar) SetMessage(msg string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.msg = msg
+	if b.active() {
+		b.render()
+	}
+}
+
+// Finish draws the bar one final time and moves to the next line, leaving the last state visible.
+// Subsequent updates are ignored. Calling Finish more than once is a no-op.
+func (b *Bar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.active() {
+		return
+	}
+	b.finished = true
+	close(b.stop)
+	b.render()
+	_, _ = io.WriteString(b.w, "\n")
+}
+
+// active reports whether the bar is rendering. The caller must hold b.mu.
+func (b *Bar) active() bool {
+	return b.started && !b.finished
+}
+
+// set updates the current value and redraws when the visible percentage changes. The caller must
+// hold b.mu.
+func (b *Bar) set(n int64) {
+	b.current = min(max(n, 0), b.total)
+	if !b.active() {
+		return
+	}
+	if pct := b.percent(); pct != b.lastPct {
+		b.render()
+	}
+}
+
+func (b *Bar) percent() int {
+	if b.total == 0 {
+		return 100
+	}
+	return int(b.current * 100 / b.total)
+}
+
+// render draws the bar. The caller must hold b.mu.
+func (b *Bar) render() {
+	pct := b.percent()
+	b.lastPct = pct
+	filled := b.cfg.width * pct / 100
+	bar := strings.Repeat("=", filled)
+	if filled < b.cfg.width {
+		bar += ">" + strings.Repeat(" ", b.cfg.width-filled-1)
+	}
+	line := fmt.Sprintf("%s[%s] %3d%%", clearLine, bar, pct)
+	if b.msg != "" {
+		line += " " + b.msg
+	}
+	_, _ = io.WriteString(b.w, line)
+}
@@ -0,0 +1,96 @@
+// Package progress provides a spinner and a determinate progress bar for long-running commands.
+//
+// Both indicators redraw a single line using carriage returns, so they are intended for interactive
+// terminals. When the writer is not a terminal (output is piped or redirected to a file), they are
+// disabled automatically and write nothing. Use [WithEnabled] to override the detection.
+//
+// Indicators should write to [cli.State] Stderr so they never mix with a command's real output:
+//
+//	sp := progress.NewSpinner(s.Stderr, "fetching repositories")
+//	sp.Start(ctx)
+//	defer sp.Stop()
+//
+//	bar := progress.NewBar(s.Stderr, int64(len(files)))
+//	bar.Start(ctx)
+//	for _, file := range files {
+//	    process(file)
+//	    bar.Add(1)
+//	}
+//	bar.Finish()
+//
+// Both indicators stop rendering when the context passed to Start is canceled.
+package progress
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// Option configures a [Spinner] or [Bar].
+type Option func(*config)
+
+type config struct {
+	enabled  *bool
+	interval time.Duration
+	width    int
+}
+
+// WithEnabled forces the indicator on or off, bypassing terminal detection. Useful in tests or when
+// the caller knows better, for example a --progress flag.
+func WithEnabled(enabled bool) Option {
+	return func(c *config) {
+		c.enabled = &enabled
+	}
+}
+
+// WithInterval sets how often the spinner advances to the next frame. Defaults to 100ms. Has no
+// effect on a [Bar], which redraws only when progress changes.
+func WithInterval(d time.Duration) Option {
+	return func(c *config) {
+		if d > 0 {
+			c.interval = d
+		}
+	}
+}
+
+// WithWidth sets the number of characters used for the bar itself, excluding the brackets and
+// percentage. Defaults to 40. Has no effect on a [Spinner].
+func WithWidth(width int) Option {
+	return func(c *config) {
+		if width > 0 {
+			c.width = width
+		}
+	}
+}
+
+func newConfig(w io.Writer, opts []Option) config {
+	cfg := config{
+		interval: 100 * time.Millisecond,
+		width:    40,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.enabled == nil {
+		enabled := isTerminal(w)
+		cfg.enabled = &enabled
+	}
+	return cfg
+}
+
+// isTerminal reports whether w is a character device, such as an interactive terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// clearLine moves the cursor to the start of the line and erases it.
+const clearLine = "\r\033[K"
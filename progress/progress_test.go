@@ -0,0 +1,128 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes from the spinner goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSpinner(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled on non-terminal writer", func(t *testing.T) {
+		t.Parallel()
+		var buf syncBuffer
+		sp := NewSpinner(&buf, "working")
+		sp.Start(context.Background())
+		sp.Stop()
+		assert.Empty(t, buf.String())
+	})
+	t.Run("renders and clears when enabled", func(t *testing.T) {
+		t.Parallel()
+		var buf syncBuffer
+		sp := NewSpinner(&buf, "working", WithEnabled(true), WithInterval(time.Millisecond))
+		sp.Start(context.Background())
+		time.Sleep(10 * time.Millisecond)
+		sp.SetMessage("almost done")
+		sp.Stop()
+		out := buf.String()
+		assert.Contains(t, out, "working")
+		assert.Contains(t, out, "almost done")
+		assert.True(t, strings.HasSuffix(out, clearLine))
+		// Stop is idempotent.
+		sp.Stop()
+	})
+	t.Run("stops on context cancellation", func(t *testing.T) {
+		t.Parallel()
+		var buf syncBuffer
+		ctx, cancel := context.WithCancel(context.Background())
+		sp := NewSpinner(&buf, "working", WithEnabled(true), WithInterval(time.Millisecond))
+		sp.Start(ctx)
+		cancel()
+		require.Eventually(t, func() bool {
+			sp.mu.Lock()
+			defer sp.mu.Unlock()
+			return !sp.running
+		}, time.Second, time.Millisecond)
+		sp.Stop()
+	})
+}
+
+func TestBar(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled on non-terminal writer", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		bar := NewBar(&buf, 10)
+		bar.Start(context.Background())
+		bar.Add(5)
+		bar.Finish()
+		assert.Empty(t, buf.String())
+	})
+	t.Run("renders progress", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		bar := NewBar(&buf, 4, WithEnabled(true), WithWidth(4))
+		bar.Start(context.Background())
+		bar.Add(1)
+		bar.SetMessage("copying")
+		bar.Add(1)
+		bar.Finish()
+		out := buf.String()
+		assert.Contains(t, out, "[>   ]   0%")
+		assert.Contains(t, out, "[=>  ]  25%")
+		assert.Contains(t, out, "[==> ]  50% copying")
+		assert.True(t, strings.HasSuffix(out, "\n"))
+	})
+	t.Run("clamps to total", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		bar := NewBar(&buf, 2, WithEnabled(true), WithWidth(2))
+		bar.Start(context.Background())
+		bar.Set(10)
+		bar.Finish()
+		assert.Contains(t, buf.String(), "[==] 100%")
+	})
+	t.Run("updates ignored after context cancellation", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		ctx, cancel := context.WithCancel(context.Background())
+		bar := NewBar(&buf, 10, WithEnabled(true))
+		bar.Start(ctx)
+		cancel()
+		require.Eventually(t, func() bool {
+			bar.mu.Lock()
+			defer bar.mu.Unlock()
+			return bar.finished
+		}, time.Second, time.Millisecond)
+		n := len(buf.String())
+		bar.Add(5)
+		bar.Finish()
+		assert.Len(t, buf.String(), n)
+	})
+}
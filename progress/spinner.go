@@ -0,0 +1,112 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Spinner is an indeterminate progress indicator that animates next to a message. It is safe for
+// concurrent use.
+type Spinner struct {
+	w   io.Writer
+	cfg config
+
+	mu      sync.Mutex
+	msg     string
+	frame   int
+	running bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewSpinner returns a Spinner that writes to w. The spinner does not render until [Spinner.Start]
+// is called.
+func NewSpinner(w io.Writer, msg string, opts ...Option) *Spinner {
+	return &Spinner{
+		w:   w,
+		cfg: newConfig(w, opts),
+		msg: msg,
+	}
+}
+
+// Start begins animating the spinner in a background goroutine. The spinner stops when
+// [Spinner.Stop] is called or ctx is canceled, whichever happens first. Calling Start on a running
+// or disabled spinner is a no-op.
+func (s *Spinner) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running || !*s.cfg.enabled {
+		return
+	}
+	s.running = true
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	s.render()
+	go s.loop(ctx, s.stop, s.done)
+}
+
+// SetMessage replaces the message displayed next to the spinner.
+func (s *Spinner) SetMessage(msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msg = msg
+	if s.running {
+		s.render()
+	}
+}
+
+// Stop halts the animation and clears the spinner line. It blocks until the background goroutine
+// has exited, so it is safe to write to the same writer afterwards. Calling Stop on a spinner that
+// is not running is a no-op.
+func (s *Spinner) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+	done := s.done
+	s.mu.Unlock()
+	<-done
+}
+
+func (s *Spinner) loop(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(s.cfg.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			s.frame = (s.frame + 1) % len(spinnerFrames)
+			s.render()
+			s.mu.Unlock()
+		case <-stop:
+			s.finish()
+			return
+		case <-ctx.Done():
+			s.finish()
+			return
+		}
+	}
+}
+
+func (s *Spinner) finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+	_, _ = io.WriteString(s.w, clearLine)
+}
+
+// render draws the current frame. The caller must hold s.mu.
+func (s *Spinner) render() {
+	_, _ = fmt.Fprintf(s.w, "%s%s %s", clearLine, spinnerFrames[s.frame], s.msg)
+}
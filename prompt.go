@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	yesFlag            = "yes"
+	nonInteractiveFlag = "non-interactive"
+)
+
+// PromptFlags registers the standard --yes and --non-interactive flags on f, so scripts can run a
+// CLI that prompts without a terminal. Register them on the root command so every subcommand
+// inherits them:
+//
+//	root.Flags = cli.FlagsFunc(func(f *flag.FlagSet) {
+//	    cli.PromptFlags(f)
+//	})
+//
+// With --yes, [Confirm] answers yes without prompting. With --non-interactive, it never prompts and
+// returns the default answer instead. Commands with prompts of their own should check
+// [State.AutoConfirm] and [State.Interactive] before reading from [State].Stdin.
+func PromptFlags(f *flag.FlagSet) {
+	f.Bool(yesFlag, false, "answer yes to all prompts")
+	f.Bool(nonInteractiveFlag, false, "never prompt, use the default answers")
+}
+
+// AutoConfirm reports whether prompts should be answered yes without asking, because a boolean
+// flag named "yes", such as the one registered by [PromptFlags], is set on a command in the path.
+func (s *State) AutoConfirm() bool {
+	return lookupBoolFlag(s, yesFlag)
+}
+
+// Interactive reports whether the command may prompt the user. It is false when a boolean flag
+// named "non-interactive", such as the one registered by [PromptFlags], is set on a command in the
+// path; prompts should then use their default answers.
+func (s *State) Interactive() bool {
+	return !lookupBoolFlag(s, nonInteractiveFlag)
+}
+
+// Confirm asks a yes/no question on s.Stderr and reads the answer from s.Stdin, so commands that
+// prompt before destructive actions can be tested by passing input through [RunOptions].Stdin.
+// The prompt is followed by "[Y/n]" or "[y/N]" depending on def, and an empty answer, or no input
+// at all, returns def. Answers are case-insensitive "y", "yes", "n", or "no"; anything else asks
+// again.
+//
+// Confirm returns true without prompting if [State.AutoConfirm] is true, and def without prompting
+// if [State.Interactive] is false. See [PromptFlags].
+//
+//	ok, err := cli.Confirm(s, "Remove all tasks?", false)
+//	if err != nil || !ok {
+//	    return err
+//	}
+func Confirm(s *State, prompt string, def bool) (bool, error) {
+	if s.AutoConfirm() {
+		return true, nil
+	}
+	if !s.Interactive() {
+		return def, nil
+	}
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	w := s.Stderr
+	if w == nil {
+		w = io.Discard
+	}
+	for {
+		fmt.Fprintf(w, "%s %s: ", prompt, hint)
+		line, err := readLine(s.Stdin)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, fmt.Errorf("failed to read answer: %w", err)
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		case "":
+			if err != nil {
+				// End the prompt line, since the user never pressed enter.
+				fmt.Fprintln(w)
+			}
+			return def, nil
+		}
+		if err != nil {
+			return def, nil
+		}
+	}
+}
+
+// readLine reads from r up to and including the next newline. It reads one byte at a time so no
+// input after the line is consumed, leaving it for the command or a later prompt. It returns
+// io.EOF if the input ends before a newline, along with any partial line.
+func readLine(r io.Reader) (string, error) {
+	if r == nil {
+		return "", io.EOF
+	}
+	var sb strings.Builder
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				return sb.String(), nil
+			}
+			sb.WriteByte(buf[0])
+		}
+		if err != nil {
+			return sb.String(), err
+		}
+	}
+}
+
+// lookupBoolFlag returns the value of the boolean flag name, or false if no command in the path
+// defines it. Flags on deeper commands take precedence, matching combineFlags.
+func lookupBoolFlag(s *State, name string) bool {
+	var value bool
+	for _, cmd := range s.path {
+		if cmd.Flags == nil {
+			continue
+		}
+		f := cmd.Flags.Lookup(name)
+		if f == nil {
+			continue
+		}
+		if getter, ok := f.Value.(flag.Getter); ok {
+			if v, ok := getter.Get().(bool); ok {
+				value = v
+			}
+		}
+	}
+	return value
+}
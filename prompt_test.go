@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirm(t *testing.T) {
+	t.Parallel()
+
+	confirm := func(t *testing.T, input string, def bool) (bool, string) {
+		t.Helper()
+		var stderr bytes.Buffer
+		s := &State{Stdin: strings.NewReader(input), Stderr: &stderr}
+		ok, err := Confirm(s, "Delete everything?", def)
+		require.NoError(t, err)
+		return ok, stderr.String()
+	}
+
+	t.Run("answers", func(t *testing.T) {
+		t.Parallel()
+		for input, want := range map[string]bool{
+			"y\n":     true,
+			"YES\n":   true,
+			" n \n":   false,
+			"No\n":    false,
+			"yes":     true,
+			"\n":      false,
+			"":        false,
+			"maybe\n": false,
+		} {
+			ok, _ := confirm(t, input, false)
+			assert.Equal(t, want, ok, "input %q", input)
+		}
+	})
+	t.Run("default shown in prompt", func(t *testing.T) {
+		t.Parallel()
+		ok, stderr := confirm(t, "\n", true)
+		assert.True(t, ok)
+		assert.Equal(t, "Delete everything? [Y/n]: ", stderr)
+		ok, stderr = confirm(t, "\n", false)
+		assert.False(t, ok)
+		assert.Equal(t, "Delete everything? [y/N]: ", stderr)
+	})
+	t.Run("invalid answer asks again", func(t *testing.T) {
+		t.Parallel()
+		ok, stderr := confirm(t, "maybe\ny\n", false)
+		assert.True(t, ok)
+		assert.Equal(t, strings.Repeat("Delete everything? [y/N]: ", 2), stderr)
+	})
+	t.Run("leaves remaining input unread", func(t *testing.T) {
+		t.Parallel()
+		stdin := strings.NewReader("y\nrest\n")
+		ok, err := Confirm(&State{Stdin: stdin}, "Continue?", false)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		line, err := readLine(stdin)
+		require.NoError(t, err)
+		assert.Equal(t, "rest", line)
+	})
+	t.Run("read error", func(t *testing.T) {
+		t.Parallel()
+		s := &State{Stdin: iotest.ErrReader(errors.New("boom"))}
+		_, err := Confirm(s, "Continue?", true)
+		require.ErrorContains(t, err, "boom")
+	})
+	t.Run("yes flag skips prompt", func(t *testing.T) {
+		t.Parallel()
+		var answered bool
+		newRoot := func() *Command {
+			return &Command{
+				Name: "app",
+				Flags: FlagsFunc(func(f *flag.FlagSet) {
+					f.Bool("yes", false, "answer yes to all prompts")
+				}),
+				SubCommands: []*Command{{
+					Name: "wipe",
+					Exec: func(ctx context.Context, s *State) error {
+						var err error
+						answered, err = Confirm(s, "Wipe?", false)
+						return err
+					},
+				}},
+			}
+		}
+		var stderr bytes.Buffer
+		err := ParseAndRun(context.Background(), newRoot(), []string{"wipe", "--yes"}, &RunOptions{
+			Stdin:  strings.NewReader("n\n"),
+			Stderr: &stderr,
+		})
+		require.NoError(t, err)
+		assert.True(t, answered)
+		assert.Empty(t, stderr.String())
+
+		err = ParseAndRun(context.Background(), newRoot(), []string{"wipe"}, &RunOptions{
+			Stdin:  strings.NewReader("n\n"),
+			Stderr: &stderr,
+		})
+		require.NoError(t, err)
+		assert.False(t, answered)
+		assert.Equal(t, "Wipe? [y/N]: ", stderr.String())
+	})
+}
+
+func TestPromptFlags(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, args ...string) (autoConfirm, interactive, answer bool, stderr string) {
+		t.Helper()
+		root := &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				PromptFlags(f)
+			}),
+			SubCommands: []*Command{{
+				Name: "wipe",
+				Exec: func(ctx context.Context, s *State) error {
+					autoConfirm, interactive = s.AutoConfirm(), s.Interactive()
+					var err error
+					answer, err = Confirm(s, "Wipe?", true)
+					return err
+				},
+			}},
+		}
+		var buf bytes.Buffer
+		err := ParseAndRun(context.Background(), root, append([]string{"wipe"}, args...), &RunOptions{
+			Stdin:  strings.NewReader("n\n"),
+			Stderr: &buf,
+		})
+		require.NoError(t, err)
+		return autoConfirm, interactive, answer, buf.String()
+	}
+
+	t.Run("prompts by default", func(t *testing.T) {
+		t.Parallel()
+		autoConfirm, interactive, answer, stderr := run(t)
+		assert.False(t, autoConfirm)
+		assert.True(t, interactive)
+		assert.False(t, answer)
+		assert.Equal(t, "Wipe? [Y/n]: ", stderr)
+	})
+	t.Run("yes", func(t *testing.T) {
+		t.Parallel()
+		autoConfirm, interactive, answer, stderr := run(t, "--yes")
+		assert.True(t, autoConfirm)
+		assert.True(t, interactive)
+		assert.True(t, answer)
+		assert.Empty(t, stderr)
+	})
+	t.Run("non-interactive uses default", func(t *testing.T) {
+		t.Parallel()
+		autoConfirm, interactive, answer, stderr := run(t, "--non-interactive")
+		assert.False(t, autoConfirm)
+		assert.False(t, interactive)
+		assert.True(t, answer)
+		assert.Empty(t, stderr)
+	})
+}
@@ -5,12 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"os"
 	"runtime"
 	"runtime/debug"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 // RunOptions specifies options for running a command.
@@ -20,10 +23,95 @@ type RunOptions struct {
 	// and [os.Stderr], respectively).
 	Stdin          io.Reader
 	Stdout, Stderr io.Writer
+
+	// Logger is the structured logger exposed as [State].Logger. If nil, the logger set on the
+	// State after [Parse] is kept, and otherwise a logger is built from the --log-level and
+	// --log-format flags registered by [LogFlags], or an info-level text logger writing to Stderr
+	// when those flags are not registered.
+	Logger *slog.Logger
+
+	// Values holds application values, such as database pools or API clients, made available to
+	// Exec functions through [State.Value]. Keys follow the same rules as [context.Context] values:
+	// use an unexported key type to avoid collisions between packages. The map is copied when the
+	// command runs.
+	Values map[any]any
+
+	// OnCommandComplete, if set, is called after the command's Exec function returns (or panics),
+	// with the command path, how long it ran, and its outcome. Use it to emit metrics or usage
+	// telemetry without wrapping every Exec function.
+	OnCommandComplete func(info CommandRunInfo)
+
+	// LookupEnv, if set, replaces [os.LookupEnv] for reading environment variables: for flags with
+	// an EnvVar parsed by [ParseAndRun], for $VAR references in flagtype.ExpandedString values
+	// and env:// references in flagtype.SecretSource values, and for [State.LookupEnv]. Tests and
+	// programs that embed commands can use it to control the environment without changing the
+	// process's own.
+	LookupEnv func(key string) (string, bool)
+
+	// Dir is the working directory for the command, exposed as [State].Dir. Flag values parsed by
+	// [ParseAndRun] that take file paths, like flagtype.Path, flagtype.GlobFiles, and
+	// flagtype.KeyPair, resolve relative paths against it, and so do @path argument files. This lets
+	// tests and programs that embed commands run them "in" a directory without calling [os.Chdir].
+	// If empty, the process working directory is used.
+	Dir string
+
+	// DebugOutput, if set, receives a trace of how [ParseAndRun] parsed the arguments: the resolved
+	// command, and the final value of every flag with the command that defines it and whether it
+	// came from the command line, an environment variable, or its default. This helps explain why a
+	// flag did not take effect in a deep command hierarchy. Setting the CLI_DEBUG environment
+	// variable to 1 turns on the same trace, written to Stderr, for [Parse] as well.
+	DebugOutput io.Writer
+
+	// HelpPager pipes help printed by [ParseAndRun] through $PAGER (or less) when it is taller than
+	// the terminal, like git does. Paging only happens when Stdout is an interactive terminal, so
+	// scripts, tests, and redirected output are unaffected.
+	HelpPager bool
+
+	// PanicStack includes the full stack trace of the panicking goroutine in the error returned
+	// when Exec panics, instead of only the location of the panic.
+	PanicStack bool
+
+	// PanicHandler, if set, is called when the command's Exec function panics, with the recovered
+	// value and the stack trace of the panic. The error it returns is returned by [Run] in place of
+	// the default panic error. Use it to write a crash report, for example to Stderr or a file, or
+	// to send it to an error tracker:
+	//
+	//	PanicHandler: func(s *cli.State, recovered any, stack []byte) error {
+	//	    name := filepath.Join(os.TempDir(), "app-crash.txt")
+	//	    _ = os.WriteFile(name, stack, 0o600)
+	//	    return fmt.Errorf("internal error: %v (crash report written to %s)", recovered, name)
+	//	}
+	PanicHandler func(s *State, recovered any, stack []byte) error
+
+	// ErrorHandler, if set, handles the error returned by [ParseAndRunMain]: it reports the error,
+	// for example with colors or as JSON for machine consumers, and returns the process exit code.
+	// The state is that of the parsed command, with its streams set even if parsing failed, or nil
+	// if the command tree was invalid. By default the error is printed to Stderr as
+	// "error: <message>", and the exit code is taken from an ExitCode() int method on the error,
+	// such as 2 for a [*UsageError], or 1.
+	ErrorHandler func(err error, s *State) int
+
+	// Args, if non-nil, makes [Run] parse these arguments before running the command, so callers
+	// that do not need to work between the two steps can skip calling [Parse]. Parse errors,
+	// including [ErrHelp], are returned as is. [ParseAndRun] ignores it.
+	Args []string
 }
 
-// Run executes the current command. It returns an error if the command has not been parsed or if
-// the command has no execution function.
+// CommandRunInfo describes a completed command run. See [RunOptions].OnCommandComplete.
+type CommandRunInfo struct {
+	// CommandPath is the space-separated path of the command that ran, such as "todo list".
+	CommandPath string
+	// Duration is how long the command's Exec function ran.
+	Duration time.Duration
+	// Err is the error returned by the command, nil on success.
+	Err error
+	// ExitCode is the process exit code suggested by Err: 0 on success, the value of an ExitCode()
+	// int method if Err (or an error it wraps) has one, and 1 otherwise.
+	ExitCode int
+}
+
+// Run executes the current command. It returns [ErrNotParsed] if the command has not been parsed,
+// unless [RunOptions].Args is set, and an error if the command has no execution function.
 //
 // The options parameter may be nil, in which case default values are used. See [RunOptions] for
 // more details.
@@ -34,8 +122,13 @@ func Run(ctx context.Context, root *Command, options *RunOptions) error {
 	if root == nil {
 		return errors.New("root command is nil")
 	}
-	if root.state == nil || len(root.state.path) == 0 {
-		return errors.New("command not parsed")
+	if options != nil && options.Args != nil {
+		if err := parse(root, options.Args, newParseConfig(options)); err != nil {
+			return err
+		}
+	}
+	if root.state == nil || len(root.state.path) == 0 || root.state.path[0] != root {
+		return ErrNotParsed
 	}
 	cmd := root.terminal()
 	if cmd == nil {
@@ -45,8 +138,59 @@ func Run(ctx context.Context, root *Command, options *RunOptions) error {
 
 	options = checkAndSetRunOptions(options)
 	updateState(root.state, options)
+	// Keep a logger the caller set on the state after Parse, but replace one from a previous Run,
+	// which may have been built from different flags.
+	if s := root.state; options.Logger != nil || s.Logger == nil || s.Logger == s.runLogger {
+		s.Logger = resolveLogger(s, options)
+		s.runLogger = s.Logger
+	}
+	root.state.values = maps.Clone(options.Values)
+
+	if options.OnCommandComplete == nil {
+		return run(ctx, cmd, root.state, options)
+	}
+	start := time.Now()
+	err := run(ctx, cmd, root.state, options)
+	options.OnCommandComplete(CommandRunInfo{
+		CommandPath: getCommandPath(root.state.path),
+		Duration:    time.Since(start),
+		Err:         err,
+		ExitCode:    exitCode(err),
+	})
+	return err
+}
+
+// ErrNotParsed is returned by [Run] when the command has not been parsed with [Parse]. It is also
+// returned when Run is given a subcommand instead of the root command that was parsed.
+var ErrNotParsed = errors.New("command not parsed")
 
-	return run(ctx, cmd, root.state)
+// ErrTimeout is returned by [Run] when a command does not finish within its [Command].Timeout. It
+// reports exit code 124 through an ExitCode method, the same code the graceful package uses for
+// timeouts.
+var ErrTimeout error = &exitError{msg: "command timed out", code: 124}
+
+// exitError is an error with an associated process exit code.
+type exitError struct {
+	msg  string
+	code int
+}
+
+func (e *exitError) Error() string { return e.msg }
+
+// ExitCode returns the process exit code associated with the error.
+func (e *exitError) ExitCode() int { return e.code }
+
+// exitCode returns the exit code for err: 0 for nil, the code reported by an ExitCode method
+// anywhere in the error chain, or 1.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return 1
 }
 
 // ParseAndRun is a convenience function that combines [Parse] and [Run] into a single call. It
@@ -66,20 +210,109 @@ func Run(ctx context.Context, root *Command, options *RunOptions) error {
 // For applications that need to perform work between parsing and execution (e.g., initializing
 // resources based on parsed flags), use [Parse] and [Run] separately.
 func ParseAndRun(ctx context.Context, root *Command, args []string, options *RunOptions) error {
-	if err := Parse(root, args); err != nil {
+	if err := parse(root, args, newParseConfig(options)); err != nil {
 		if errors.Is(err, ErrHelp) {
 			options = checkAndSetRunOptions(options)
-			_, _ = fmt.Fprintln(options.Stdout, DefaultUsage(root))
+			if root.state != nil && root.state.helpJSON {
+				data, err := HelpJSON(root)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintln(options.Stdout, string(data))
+				return nil
+			}
+			writeHelp(DefaultUsage(root), options)
 			return nil
 		}
 		return err
 	}
+	if options != nil && options.Args != nil {
+		// Run would parse again, replacing the arguments just parsed.
+		opt := *options
+		opt.Args = nil
+		options = &opt
+	}
 	return Run(ctx, root, options)
 }
 
-func run(ctx context.Context, cmd *Command, state *State) (retErr error) {
+// newParseConfig returns the parse settings for options, which may be nil.
+func newParseConfig(options *RunOptions) parseConfig {
+	cfg := parseConfig{lookupEnv: os.LookupEnv}
+	var debug io.Writer
+	stderr := io.Writer(os.Stderr)
+	if options != nil {
+		if options.LookupEnv != nil {
+			cfg.lookupEnv = options.LookupEnv
+		}
+		if options.Stderr != nil {
+			stderr = options.Stderr
+		}
+		cfg.dir = options.Dir
+		debug = options.DebugOutput
+	}
+	cfg.debug = debugOutput(debug, cfg.lookupEnv, stderr)
+	return cfg
+}
+
+// osExit is a variable that can be mocked in tests.
+var osExit = os.Exit
+
+// ParseAndRunMain is like [ParseAndRun] but handles the error itself, for use as the whole body of
+// main:
+//
+//	func main() {
+//	    cli.ParseAndRunMain(context.Background(), root, os.Args[1:], nil)
+//	}
+//
+// If the command fails, the error is passed to [RunOptions].ErrorHandler, and the process exits
+// with the code it returns. It returns normally when the command succeeds or the handler returns
+// 0.
+func ParseAndRunMain(ctx context.Context, root *Command, args []string, options *RunOptions) {
+	err := ParseAndRun(ctx, root, args, options)
+	if err == nil {
+		return
+	}
+	options = checkAndSetRunOptions(options)
+	var state *State
+	if root != nil && root.state != nil {
+		state = root.state
+		// Parse errors happen before Run sets up the state's streams.
+		updateState(state, options)
+	}
+	handler := options.ErrorHandler
+	if handler == nil {
+		handler = func(err error, _ *State) int {
+			_, _ = fmt.Fprintf(options.Stderr, "error: %v\n", err)
+			return exitCode(err)
+		}
+	}
+	if code := handler(err, state); code != 0 {
+		osExit(code)
+	}
+}
+
+func run(ctx context.Context, cmd *Command, state *State, options *RunOptions) (retErr error) {
+	if cmd.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, cmd.Timeout, ErrTimeout)
+		defer cancel()
+		// Deferred before the recover below so it also sees errors from panics.
+		defer func() {
+			if retErr != nil && context.Cause(ctx) == ErrTimeout {
+				retErr = fmt.Errorf("%w after %s: %w", ErrTimeout, cmd.Timeout, retErr)
+			}
+		}()
+	}
 	defer func() {
 		if r := recover(); r != nil {
+			if options.PanicHandler != nil {
+				retErr = options.PanicHandler(state, r, debug.Stack())
+				return
+			}
+			if options.PanicStack {
+				retErr = fmt.Errorf("panic: %v\n\n%s", r, debug.Stack())
+				return
+			}
 			switch err := r.(type) {
 			case error:
 				// If error is from cli package (e.g., flag type mismatch), don't add location info
@@ -107,6 +340,12 @@ func updateState(s *State, opt *RunOptions) {
 	if s.Stderr == nil {
 		s.Stderr = opt.Stderr
 	}
+	if s.lookupEnv == nil {
+		s.lookupEnv = opt.LookupEnv
+	}
+	if s.Dir == "" {
+		s.Dir = opt.Dir
+	}
 }
 
 func checkAndSetRunOptions(opt *RunOptions) *RunOptions {
@@ -122,6 +361,9 @@ func checkAndSetRunOptions(opt *RunOptions) *RunOptions {
 	if opt.Stderr == nil {
 		opt.Stderr = os.Stderr
 	}
+	if opt.LookupEnv == nil {
+		opt.LookupEnv = os.LookupEnv
+	}
 	return opt
 }
 
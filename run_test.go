@@ -3,9 +3,13 @@ package cli
 import (
 	"bytes"
 	"context"
+	"errors"
 	"flag"
+	"fmt"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -112,6 +116,40 @@ func TestRun(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "panic")
 	})
+	t.Run("panic with full stack", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{
+			Name: "panic",
+			Exec: func(ctx context.Context, s *State) error {
+				panic("test panic")
+			},
+		}
+		err := Run(context.Background(), root, &RunOptions{Args: []string{}, PanicStack: true})
+		require.Error(t, err)
+		require.True(t, strings.HasPrefix(err.Error(), "panic: test panic\n\ngoroutine "), err.Error())
+		require.Contains(t, err.Error(), "runtime/debug.Stack")
+	})
+	t.Run("panic handler", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{
+			Name: "panic",
+			Exec: func(ctx context.Context, s *State) error {
+				panic("test panic")
+			},
+		}
+		var gotStack []byte
+		var gotState *State
+		err := Run(context.Background(), root, &RunOptions{
+			Args: []string{},
+			PanicHandler: func(s *State, recovered any, stack []byte) error {
+				gotState, gotStack = s, stack
+				return fmt.Errorf("crashed: %v", recovered)
+			},
+		})
+		require.EqualError(t, err, "crashed: test panic")
+		require.Same(t, root.state, gotState)
+		require.Contains(t, string(gotStack), "TestRun")
+	})
 	t.Run("run before parse", func(t *testing.T) {
 		t.Parallel()
 		root := &Command{
@@ -119,9 +157,49 @@ func TestRun(t *testing.T) {
 			Exec: func(ctx context.Context, s *State) error { return nil },
 		}
 		err := Run(context.Background(), root, nil)
-		require.Error(t, err)
+		require.ErrorIs(t, err, ErrNotParsed)
 		require.Contains(t, err.Error(), "command not parsed")
 	})
+	t.Run("run subcommand of parsed root", func(t *testing.T) {
+		t.Parallel()
+		sub := &Command{
+			Name: "sub",
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+		root := &Command{Name: "test", SubCommands: []*Command{sub}}
+		require.NoError(t, Parse(root, []string{"sub"}))
+		err := Run(context.Background(), sub, nil)
+		require.ErrorIs(t, err, ErrNotParsed)
+	})
+	t.Run("run with args parses", func(t *testing.T) {
+		t.Parallel()
+		var got []string
+		root := &Command{
+			Name: "test",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Bool("force", false, "force")
+			}),
+			SubCommands: []*Command{{
+				Name: "sub",
+				Exec: func(ctx context.Context, s *State) error {
+					got = s.Args
+					if !GetFlag[bool](s, "force") {
+						return errors.New("force not set")
+					}
+					return nil
+				},
+			}},
+		}
+		err := Run(context.Background(), root, &RunOptions{Args: []string{"sub", "--force", "a"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a"}, got)
+
+		err = Run(context.Background(), root, &RunOptions{Args: []string{"--help"}})
+		require.ErrorIs(t, err, ErrHelp)
+		err = Run(context.Background(), root, &RunOptions{Args: []string{"nope"}})
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrNotParsed)
+	})
 	t.Run("concurrent state access", func(t *testing.T) {
 		t.Parallel()
 		root := &Command{
@@ -228,4 +306,203 @@ func TestRun(t *testing.T) {
 			require.Equal(t, val, GetFlag[string](root.state, "text"))
 		}
 	})
+	t.Run("values", func(t *testing.T) {
+		t.Parallel()
+		type clientKey struct{}
+		type client struct{ name string }
+		var got any
+		root := &Command{
+			Name: "app",
+			SubCommands: []*Command{
+				{
+					Name: "sub",
+					Exec: func(ctx context.Context, s *State) error {
+						got = s.Value(clientKey{})
+						require.Nil(t, s.Value("missing"))
+						return nil
+					},
+				},
+			},
+		}
+		c := &client{name: "api"}
+		values := map[any]any{clientKey{}: c}
+		err := ParseAndRun(context.Background(), root, []string{"sub"}, &RunOptions{Values: values})
+		require.NoError(t, err)
+		require.Same(t, c, got)
+	})
+	t.Run("values without options", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{
+			Name: "app",
+			Exec: func(ctx context.Context, s *State) error {
+				require.Nil(t, s.Value("anything"))
+				return nil
+			},
+		}
+		require.NoError(t, ParseAndRun(context.Background(), root, nil, nil))
+	})
+	t.Run("on command complete", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{
+			Name: "app",
+			SubCommands: []*Command{
+				{
+					Name: "ok",
+					Exec: func(ctx context.Context, s *State) error {
+						time.Sleep(time.Millisecond)
+						return nil
+					},
+				},
+				{
+					Name: "fail",
+					Exec: func(ctx context.Context, s *State) error { return errors.New("boom") },
+				},
+				{
+					Name: "exit",
+					Exec: func(ctx context.Context, s *State) error {
+						return fmt.Errorf("wrapped: %w", exitCodeError(3))
+					},
+				},
+				{
+					Name: "panic",
+					Exec: func(ctx context.Context, s *State) error { panic("oops") },
+				},
+			},
+		}
+		var infos []CommandRunInfo
+		opts := &RunOptions{
+			OnCommandComplete: func(info CommandRunInfo) { infos = append(infos, info) },
+		}
+		require.NoError(t, ParseAndRun(context.Background(), root, []string{"ok"}, opts))
+		require.Error(t, ParseAndRun(context.Background(), root, []string{"fail"}, opts))
+		require.Error(t, ParseAndRun(context.Background(), root, []string{"exit"}, opts))
+		require.Error(t, ParseAndRun(context.Background(), root, []string{"panic"}, opts))
+
+		require.Len(t, infos, 4)
+		require.Equal(t, "app ok", infos[0].CommandPath)
+		require.NoError(t, infos[0].Err)
+		require.Equal(t, 0, infos[0].ExitCode)
+		require.GreaterOrEqual(t, infos[0].Duration, time.Millisecond)
+
+		require.Equal(t, "app fail", infos[1].CommandPath)
+		require.EqualError(t, infos[1].Err, "boom")
+		require.Equal(t, 1, infos[1].ExitCode)
+
+		require.Equal(t, 3, infos[2].ExitCode)
+
+		require.Equal(t, "app panic", infos[3].CommandPath)
+		require.ErrorContains(t, infos[3].Err, "panic: oops")
+		require.Equal(t, 1, infos[3].ExitCode)
+	})
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		root := &Command{
+			Name: "app",
+			SubCommands: []*Command{
+				{
+					Name:    "slow",
+					Timeout: 10 * time.Millisecond,
+					Exec: func(ctx context.Context, s *State) error {
+						<-ctx.Done()
+						return ctx.Err()
+					},
+				},
+				{
+					Name:    "fast",
+					Timeout: time.Minute,
+					Exec: func(ctx context.Context, s *State) error {
+						_, ok := ctx.Deadline()
+						require.True(t, ok)
+						return nil
+					},
+				},
+			},
+		}
+		var info CommandRunInfo
+		opts := &RunOptions{
+			OnCommandComplete: func(i CommandRunInfo) { info = i },
+		}
+		err := ParseAndRun(context.Background(), root, []string{"slow"}, opts)
+		require.ErrorIs(t, err, ErrTimeout)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.EqualError(t, err, "command timed out after 10ms: context deadline exceeded")
+		require.Equal(t, 124, info.ExitCode)
+
+		require.NoError(t, ParseAndRun(context.Background(), root, []string{"fast"}, opts))
+
+		// Cancellation by the caller is not a timeout.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err = ParseAndRun(ctx, root, []string{"slow"}, opts)
+		require.ErrorIs(t, err, context.Canceled)
+		require.NotErrorIs(t, err, ErrTimeout)
+	})
+}
+
+type exitCodeError int
+
+func (e exitCodeError) Error() string { return "exit " + strconv.Itoa(int(e)) }
+func (e exitCodeError) ExitCode() int { return int(e) }
+
+func TestParseAndRunMain(t *testing.T) {
+	// Not parallel: replaces osExit.
+	exitCode := -1
+	original := osExit
+	t.Cleanup(func() { osExit = original })
+	osExit = func(code int) { exitCode = code }
+
+	newRoot := func() *Command {
+		return &Command{
+			Name: "app",
+			Exec: func(ctx context.Context, s *State) error {
+				if len(s.Args) > 0 {
+					return errors.New(s.Args[0])
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		exitCode = -1
+		var stderr bytes.Buffer
+		ParseAndRunMain(context.Background(), newRoot(), nil, &RunOptions{Stderr: &stderr})
+		require.Equal(t, -1, exitCode)
+		require.Empty(t, stderr.String())
+	})
+	t.Run("default handler", func(t *testing.T) {
+		exitCode = -1
+		var stderr bytes.Buffer
+		ParseAndRunMain(context.Background(), newRoot(), []string{"boom"}, &RunOptions{Stderr: &stderr})
+		require.Equal(t, 1, exitCode)
+		require.Equal(t, "error: boom\n", stderr.String())
+
+		stderr.Reset()
+		ParseAndRunMain(context.Background(), newRoot(), []string{"--unknown"}, &RunOptions{Stderr: &stderr})
+		require.Equal(t, 2, exitCode)
+		require.Equal(t, "error: command \"app\": flag provided but not defined: -unknown\n", stderr.String())
+	})
+	t.Run("custom handler", func(t *testing.T) {
+		exitCode = -1
+		var stderr bytes.Buffer
+		var gotErr error
+		ParseAndRunMain(context.Background(), newRoot(), []string{"--unknown"}, &RunOptions{
+			Stderr: &stderr,
+			ErrorHandler: func(err error, s *State) int {
+				gotErr = err
+				_, _ = fmt.Fprintf(s.Stderr, "{\"error\":%q}\n", err)
+				return 64
+			},
+		})
+		require.Equal(t, 64, exitCode)
+		require.ErrorIs(t, gotErr, ErrUsage)
+		require.Equal(t, "{\"error\":\"command \\\"app\\\": flag provided but not defined: -unknown\"}\n", stderr.String())
+	})
+	t.Run("handler returns zero", func(t *testing.T) {
+		exitCode = -1
+		ParseAndRunMain(context.Background(), newRoot(), []string{"boom"}, &RunOptions{
+			ErrorHandler: func(err error, s *State) int { return 0 },
+		})
+		require.Equal(t, -1, exitCode)
+	})
 }
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// sensitiveMask replaces the value of a sensitive flag wherever the package prints it.
+const sensitiveMask = "*****"
+
+// sensitiveFlagSet builds the set of names, including short aliases, of the flags marked as
+// sensitive in FlagOptions across the command path.
+func sensitiveFlagSet(path []*Command) map[string]bool {
+	m := make(map[string]bool)
+	for _, cmd := range path {
+		for _, fo := range cmd.FlagOptions {
+			if !fo.Sensitive {
+				continue
+			}
+			m[fo.Name] = true
+			if fo.Short != "" {
+				m[fo.Short] = true
+			}
+		}
+	}
+	return m
+}
+
+// invalidValueRegex matches the error the flag package returns when a flag value fails to parse.
+var invalidValueRegex = regexp.MustCompile(`invalid value ("(?:[^"\\]|\\.)*") for flag -([^:\s]+)`)
+
+// redactParseError masks the value of a sensitive flag in err, which the flag package includes
+// when the value fails to parse. The value is masked everywhere in the message, since errors from
+// the flag's Set method may repeat it.
+func redactParseError(err error, sensitive map[string]bool) error {
+	msg := err.Error()
+	m := invalidValueRegex.FindStringSubmatch(msg)
+	if m == nil || !sensitive[m[2]] {
+		return err
+	}
+	value, uerr := strconv.Unquote(m[1])
+	if uerr != nil || value == "" {
+		return err
+	}
+	msg = strings.ReplaceAll(msg, m[1], strconv.Quote(sensitiveMask))
+	msg = strings.ReplaceAll(msg, value, sensitiveMask)
+	return errors.New(msg)
+}
+
+// maskedValue is passed to [State.VisitFlags] for sensitive flags. It sets the underlying value
+// but prints as a mask.
+type maskedValue struct {
+	flag.Value
+}
+
+func (v maskedValue) String() string {
+	if v.Value.String() == "" {
+		return ""
+	}
+	return sensitiveMask
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSensitiveFlags(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func() *Command {
+		return &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.Func("token", "API token", func(s string) error {
+					if len(s) < 8 {
+						return fmt.Errorf("token %s is too short", s)
+					}
+					return nil
+				})
+				f.String("password", "hunter22", "database password")
+				f.Int("port", 0, "listen port")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "token", Short: "t", Sensitive: true},
+				{Name: "password", Sensitive: true, EnvVar: "APP_PASSWORD"},
+			},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+	}
+
+	t.Run("parse error", func(t *testing.T) {
+		t.Parallel()
+		for _, arg := range []string{"--token=s3cr3t", "-t=s3cr3t"} {
+			err := Parse(newRoot(), []string{arg})
+			require.Error(t, err)
+			assert.NotContains(t, err.Error(), "s3cr3t")
+			assert.Contains(t, err.Error(), `invalid value "*****" for flag`)
+			assert.Contains(t, err.Error(), "token ***** is too short")
+		}
+	})
+	t.Run("other flags not masked", func(t *testing.T) {
+		t.Parallel()
+		err := Parse(newRoot(), []string{"--port=http"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid value "http" for flag -port`)
+	})
+	t.Run("environment error", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		root.Flags.Lookup("password").Value = &intValueForTest{}
+		err := parse(root, nil, parseConfig{lookupEnv: func(string) (string, bool) { return "letmein", true }})
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "letmein")
+		assert.Contains(t, err.Error(), `invalid value "*****" for flag -password from environment variable APP_PASSWORD`)
+	})
+	t.Run("help hides default", func(t *testing.T) {
+		t.Parallel()
+		usage := DefaultUsage(newRoot())
+		assert.Contains(t, usage, "database password (env: APP_PASSWORD)\n")
+		assert.NotContains(t, usage, "hunter22")
+	})
+	t.Run("visit flags", func(t *testing.T) {
+		t.Parallel()
+		root := newRoot()
+		require.NoError(t, Parse(root, []string{"--token=0123456789"}))
+		values := make(map[string]string)
+		root.state.VisitFlags(func(name string, value flag.Value, opt FlagOption) {
+			values[name] = value.String()
+		})
+		// Func values always print as empty, which needs no mask.
+		assert.Equal(t, map[string]string{"password": "*****", "port": "0", "token": ""}, values)
+		assert.Equal(t, "hunter22", GetFlag[string](root.state, "password"))
+	})
+}
+
+// intValueForTest is an integer flag.Value whose Set error repeats the input.
+type intValueForTest struct{ n int }
+
+func (v *intValueForTest) String() string { return fmt.Sprint(v.n) }
+
+func (v *intValueForTest) Set(s string) error {
+	_, err := fmt.Sscan(s, &v.n)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", s)
+	}
+	return nil
+}
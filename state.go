@@ -1,9 +1,14 @@
 package cli
 
 import (
+	"cmp"
 	"flag"
 	"fmt"
 	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
 )
 
 // State holds command information during Exec function execution, allowing child commands to access
@@ -16,9 +21,191 @@ type State struct {
 	Stdin          io.Reader
 	Stdout, Stderr io.Writer
 
+	// Dir is the working directory from [RunOptions].Dir, or "" for the process working directory.
+	// Commands that work with relative paths should resolve them against Dir when it is set.
+	Dir string
+
+	// Logger is the structured logger for the run, shared by the command and any middleware. It is
+	// set by [Run] from [RunOptions] or built from the flags registered by [LogFlags], unless it was
+	// set after [Parse]. See [RunOptions] for details.
+	Logger *slog.Logger
+
 	// path is the command hierarchy from the root command to the current command. The root command
 	// is the first element in the path, and the terminal command is the last element.
 	path []*Command
+
+	// values are the application values from [RunOptions].Values.
+	values map[any]any
+
+	// lookupEnv is [RunOptions].LookupEnv.
+	lookupEnv func(string) (string, bool)
+	// runLogger is the Logger set by the last [Run], so a later Run can tell it apart from one set
+	// by the caller.
+	runLogger *slog.Logger
+
+	// helpAll is set by [Parse] when help was requested with --help-all, so [DefaultUsage] includes
+	// hidden flags.
+	helpAll bool
+	// helpJSON is set by [Parse] when help was requested with --help=json, so [ParseAndRun] prints
+	// [HelpJSON] instead of [DefaultUsage].
+	helpJSON bool
+}
+
+// Value returns the value associated with key in [RunOptions].Values, or nil if there is none. It
+// lets applications pass dependencies from main into Exec functions without package-level globals:
+//
+//	type dbKey struct{}
+//
+//	err := cli.ParseAndRun(ctx, root, os.Args[1:], &cli.RunOptions{
+//	    Values: map[any]any{dbKey{}: db},
+//	})
+//
+//	// In an Exec function:
+//	db := s.Value(dbKey{}).(*sql.DB)
+func (s *State) Value(key any) any {
+	return s.values[key]
+}
+
+// Arg returns the positional argument at index i of Args, or "" if there are not that many
+// arguments.
+func (s *State) Arg(i int) string {
+	if i < 0 || i >= len(s.Args) {
+		return ""
+	}
+	return s.Args[i]
+}
+
+// ArgInt returns the positional argument at index i of Args parsed as a base-10 integer. It
+// returns a [*UsageError], numbering arguments from 1, if the argument is missing or is not an
+// integer:
+//
+//	id, err := s.ArgInt(0)
+//	if err != nil {
+//	    return fmt.Errorf("task ID: %w", err)
+//	}
+func (s *State) ArgInt(i int) (int, error) {
+	if i < 0 || i >= len(s.Args) {
+		return 0, usageErrorf(tr("missing argument %d"), i+1)
+	}
+	n, err := strconv.Atoi(s.Args[i])
+	if err != nil {
+		return 0, usageErrorf(tr("argument %d: invalid integer %q"), i+1, s.Args[i])
+	}
+	return n, nil
+}
+
+// ArgsAfter returns the positional arguments that follow the first n, or nil if there are no more
+// than n. It suits commands like "app exec <name> [args...]" that forward the rest.
+func (s *State) ArgsAfter(n int) []string {
+	if n < 0 {
+		n = 0
+	}
+	if n >= len(s.Args) {
+		return nil
+	}
+	return s.Args[n:]
+}
+
+// LookupEnv returns the value of the environment variable named by key and whether it is set. It
+// uses [RunOptions].LookupEnv when provided, and the process environment otherwise, so Exec
+// functions that read the environment through State can be tested without changing it.
+func (s *State) LookupEnv(key string) (string, bool) {
+	if s.lookupEnv != nil {
+		return s.lookupEnv(key)
+	}
+	return os.LookupEnv(key)
+}
+
+// Getenv returns the value of the environment variable named by key, or "" if it is not set. See
+// [State.LookupEnv].
+func (s *State) Getenv(key string) string {
+	v, _ := s.LookupEnv(key)
+	return v
+}
+
+// Command returns the command being executed, the last command in the resolved path. It returns nil
+// if the state has not been populated by [Parse].
+func (s *State) Command() *Command {
+	if len(s.path) == 0 {
+		return nil
+	}
+	return s.path[len(s.path)-1]
+}
+
+// Root returns the root command of the hierarchy. It returns nil if the state has not been
+// populated by [Parse].
+func (s *State) Root() *Command {
+	if len(s.path) == 0 {
+		return nil
+	}
+	return s.path[0]
+}
+
+// CommandPath returns the names of the commands from the root to the command being executed,
+// separated by spaces, such as "todo list archived". This is useful as a label in logs and metrics.
+func (s *State) CommandPath() string {
+	return getCommandPath(s.path)
+}
+
+// VisitFlags calls fn for each flag available to the command being executed, in lexicographical
+// order of name: the command's own flags and those it inherits from its ancestors. When a child
+// redefines a flag, only the child's flag is visited, matching how the flag is parsed. opt is the
+// flag's [FlagOption], or one with only Name set if it has none. The value of a Sensitive flag
+// prints as a mask; use [GetFlag] to read it.
+//
+// This lets middleware log or redact the effective flag values without knowing the command's flags
+// in advance:
+//
+//	s.VisitFlags(func(name string, value flag.Value, opt cli.FlagOption) {
+//	    s.Logger.Debug("flag", "name", name, "value", value.String())
+//	})
+func (s *State) VisitFlags(fn func(name string, value flag.Value, opt FlagOption)) {
+	type entry struct {
+		f   *flag.Flag
+		opt FlagOption
+	}
+	var entries []entry
+	seen := make(map[string]bool)
+	terminalIdx := len(s.path) - 1
+	for i := terminalIdx; i >= 0; i-- {
+		cmd := s.path[i]
+		if cmd.Flags == nil {
+			continue
+		}
+		localFlags := localFlagSet(cmd)
+		opts := flagOptionMap(cmd.FlagOptions)
+		cmd.Flags.VisitAll(func(f *flag.Flag) {
+			if seen[f.Name] || (i < terminalIdx && localFlags[f.Name]) {
+				return
+			}
+			seen[f.Name] = true
+			opt, ok := opts[f.Name]
+			if !ok {
+				opt = FlagOption{Name: f.Name}
+			}
+			entries = append(entries, entry{f: f, opt: opt})
+		})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return cmp.Compare(a.f.Name, b.f.Name)
+	})
+	for _, e := range entries {
+		var value flag.Value = e.f.Value
+		if e.opt.Sensitive {
+			value = maskedValue{value}
+		}
+		fn(e.f.Name, value, e.opt)
+	}
+}
+
+// FlagNames returns the names of the flags available to the command being executed, in the order
+// of [State.VisitFlags].
+func (s *State) FlagNames() []string {
+	var names []string
+	s.VisitFlags(func(name string, _ flag.Value, _ FlagOption) {
+		names = append(names, name)
+	})
+	return names
 }
 
 // GetFlag retrieves a flag value by name from the command hierarchy. It first checks the current
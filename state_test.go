@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"context"
 	"flag"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/pressly/cli/flagtype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -48,3 +52,179 @@ func TestGetFlag(t *testing.T) {
 		_ = GetFlag[int](state, "version")
 	})
 }
+
+func TestStateCommand(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resolved path", func(t *testing.T) {
+		t.Parallel()
+		archived := &Command{Name: "archived", Exec: func(ctx context.Context, s *State) error { return nil }}
+		list := &Command{Name: "list", SubCommands: []*Command{archived}}
+		root := &Command{Name: "todo", SubCommands: []*Command{list}}
+		var got *State
+		archived.Exec = func(ctx context.Context, s *State) error {
+			got = s
+			return nil
+		}
+		err := ParseAndRun(context.Background(), root, []string{"list", "archived"}, nil)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Same(t, archived, got.Command())
+		assert.Same(t, root, got.Root())
+		assert.Equal(t, "todo list archived", got.CommandPath())
+	})
+	t.Run("empty state", func(t *testing.T) {
+		t.Parallel()
+		s := &State{}
+		assert.Nil(t, s.Command())
+		assert.Nil(t, s.Root())
+		assert.Equal(t, "", s.CommandPath())
+	})
+}
+
+func TestStateLookupEnv(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"APP_PORT": "9090", "APP_REGION": "eu"}
+	lookupEnv := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+	root := &Command{
+		Name: "app",
+		Flags: FlagsFunc(func(f *flag.FlagSet) {
+			f.Int("port", 8080, "listen port")
+		}),
+		FlagOptions: []FlagOption{{Name: "port", EnvVar: "APP_PORT"}},
+		Exec: func(ctx context.Context, s *State) error {
+			assert.Equal(t, 9090, GetFlag[int](s, "port"))
+			assert.Equal(t, "eu", s.Getenv("APP_REGION"))
+			_, ok := s.LookupEnv("PATH")
+			assert.False(t, ok)
+			return nil
+		},
+	}
+	err := ParseAndRun(context.Background(), root, nil, &RunOptions{LookupEnv: lookupEnv})
+	require.NoError(t, err)
+}
+
+func TestLookupEnvFlagTypes(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"APP_HOME": "/srv/app", "APP_TOKEN": "s3cret"}
+	lookupEnv := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+	root := &Command{
+		Name: "app",
+		Flags: FlagsFunc(func(f *flag.FlagSet) {
+			f.Var(flagtype.ExpandedString(flagtype.WithErrorOnUnset()), "data", "data directory")
+			f.Var(flagtype.SecretSource(), "token", "API token")
+		}),
+		Exec: func(ctx context.Context, s *State) error {
+			assert.Equal(t, "/srv/app/data", GetFlag[string](s, "data"))
+			assert.Equal(t, "s3cret", GetFlag[flagtype.SecretString](s, "token").Expose())
+			return nil
+		},
+	}
+	args := []string{"--data=${APP_HOME}/data", "--token=env://APP_TOKEN"}
+	err := ParseAndRun(context.Background(), root, args, &RunOptions{LookupEnv: lookupEnv})
+	require.NoError(t, err)
+}
+
+func TestStateDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"tasks.json": "{}",
+		"token.txt":  "s3cret\n",
+		"args.txt":   "--src=*.json --token=file://token.txt",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	root := &Command{
+		Name: "app",
+		Flags: FlagsFunc(func(f *flag.FlagSet) {
+			f.Var(flagtype.ExistingFile(), "file", "tasks file")
+			f.Var(flagtype.GlobFiles(), "src", "source files")
+			f.Var(flagtype.SecretSource(), "token", "API token")
+		}),
+		Exec: func(ctx context.Context, s *State) error {
+			assert.Equal(t, dir, s.Dir)
+			assert.Equal(t, filepath.Join(dir, "tasks.json"), GetFlag[string](s, "file"))
+			assert.Equal(t, []string{filepath.Join(dir, "tasks.json")}, GetFlag[[]string](s, "src"))
+			assert.Equal(t, "s3cret", GetFlag[flagtype.SecretString](s, "token").Expose())
+			return nil
+		},
+	}
+	err := ParseAndRun(context.Background(), root, []string{"--file=tasks.json", "@args.txt"}, &RunOptions{Dir: dir})
+	require.NoError(t, err)
+
+	// Without a directory, relative paths are resolved against the process working directory.
+	err = Parse(root, []string{"--file=tasks.json"})
+	require.ErrorContains(t, err, `file "tasks.json" does not exist`)
+}
+
+func TestStateArgs(t *testing.T) {
+	t.Parallel()
+
+	s := &State{Args: []string{"exec", "42", "ls", "-la"}}
+	assert.Equal(t, "exec", s.Arg(0))
+	assert.Equal(t, "-la", s.Arg(3))
+	assert.Equal(t, "", s.Arg(4))
+	assert.Equal(t, "", s.Arg(-1))
+
+	n, err := s.ArgInt(1)
+	require.NoError(t, err)
+	assert.Equal(t, 42, n)
+	_, err = s.ArgInt(0)
+	assert.EqualError(t, err, `argument 1: invalid integer "exec"`)
+	_, err = s.ArgInt(4)
+	assert.EqualError(t, err, "missing argument 5")
+
+	assert.Equal(t, []string{"ls", "-la"}, s.ArgsAfter(2))
+	assert.Equal(t, s.Args, s.ArgsAfter(0))
+	assert.Nil(t, s.ArgsAfter(4))
+}
+
+func TestStateVisitFlags(t *testing.T) {
+	t.Parallel()
+
+	root := &Command{
+		Name: "app",
+		Flags: FlagsFunc(func(f *flag.FlagSet) {
+			f.Bool("verbose", false, "verbose output")
+			f.String("output", "text", "output format")
+			f.Bool("version", false, "show version")
+		}),
+		FlagOptions: []FlagOption{
+			{Name: "verbose", Short: "v"},
+			{Name: "version", Local: true},
+		},
+		SubCommands: []*Command{{
+			Name: "get",
+			Flags: FlagsFunc(func(f *flag.FlagSet) {
+				f.String("output", "json", "output format")
+				f.String("token", "", "API token")
+			}),
+			FlagOptions: []FlagOption{{Name: "token", Required: true}},
+			Exec:        func(ctx context.Context, s *State) error { return nil },
+		}},
+	}
+	require.NoError(t, Parse(root, []string{"get", "-v", "--token=abc"}))
+
+	values := make(map[string]string)
+	var required []string
+	root.state.VisitFlags(func(name string, value flag.Value, opt FlagOption) {
+		assert.Equal(t, name, opt.Name)
+		values[name] = value.String()
+		if opt.Required {
+			required = append(required, name)
+		}
+	})
+	assert.Equal(t, map[string]string{"output": "json", "token": "abc", "verbose": "true"}, values)
+	assert.Equal(t, []string{"token"}, required)
+	assert.Equal(t, []string{"output", "token", "verbose"}, root.state.FlagNames())
+}
@@ -16,6 +16,9 @@ const defaultTerminalWidth = 80
 // DefaultUsage returns the default usage string for the command hierarchy. It is used when the
 // command does not provide a custom usage function. The usage string includes the command's short
 // help, usage pattern, available subcommands, and flags.
+//
+// After [Parse], root may be any command in the resolved path, such as the [HelpError].Command
+// returned when help is requested; the usage is always that of the resolved command.
 func DefaultUsage(root *Command) string {
 	if root == nil {
 		return ""
@@ -30,31 +33,39 @@ func DefaultUsage(root *Command) string {
 		return terminalCmd.UsageFunc(terminalCmd)
 	}
 
-	if terminalCmd.ShortHelp != "" {
+	if header := inheritedHelpText(root, func(c *Command) string { return c.HelpHeader }); header != "" {
+		b.WriteString(strings.TrimRight(header, "\n"))
+		b.WriteString("\n\n")
+	}
+
+	if terminalCmd.LongHelp != "" {
+		b.WriteString(textutil.RenderMarkdown(terminalCmd.LongHelp, defaultTerminalWidth))
+		b.WriteString("\n\n")
+	} else if terminalCmd.ShortHelp != "" {
 		b.WriteString(terminalCmd.ShortHelp)
 		b.WriteString("\n\n")
 	}
 
-	b.WriteString("Usage:\n")
-	if terminalCmd.Usage != "" {
-		b.WriteString("  " + terminalCmd.Usage + "\n")
-	} else {
-		usage := terminalCmd.Name
-		if root.state != nil && len(root.state.path) > 0 {
-			usage = getCommandPath(root.state.path)
-		}
-		if terminalCmd.Flags != nil {
-			usage += " [flags]"
-		}
-		if len(terminalCmd.SubCommands) > 0 {
-			usage += " <command>"
+	b.WriteString(tr("Usage:") + "\n")
+	b.WriteString("  " + usageLine(root, terminalCmd) + "\n")
+	b.WriteString("\n")
+
+	if terminalCmd.Example != "" {
+		b.WriteString(tr("Examples:") + "\n")
+		for _, line := range strings.Split(strings.Trim(terminalCmd.Example, "\n"), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "```") {
+				continue
+			}
+			if line = strings.TrimRight(line, " \t"); line != "" {
+				b.WriteString("  " + line)
+			}
+			b.WriteString("\n")
 		}
-		b.WriteString("  " + usage + "\n")
+		b.WriteString("\n")
 	}
-	b.WriteString("\n")
 
 	if len(terminalCmd.SubCommands) > 0 {
-		b.WriteString("Available Commands:\n")
+		b.WriteString(tr("Available Commands:") + "\n")
 		sortedCommands := slices.Clone(terminalCmd.SubCommands)
 		slices.SortFunc(sortedCommands, func(a, b *Command) int {
 			return cmp.Compare(a.Name, b.Name)
@@ -76,19 +87,124 @@ func DefaultUsage(root *Command) string {
 				continue
 			}
 
-			lines := textutil.Wrap(sub.ShortHelp, wrapWidth)
 			padding := strings.Repeat(" ", maxNameLen-len(sub.Name)+4)
-			fmt.Fprintf(&b, "  %s%s%s\n", sub.Name, padding, lines[0])
+			fmt.Fprintf(&b, "  %s%s%s\n", sub.Name, padding, textutil.WrapHanging(sub.ShortHelp, wrapWidth, nameWidth+2))
+		}
+		b.WriteString("\n")
+	}
 
-			indentPadding := strings.Repeat(" ", nameWidth+2)
-			for _, line := range lines[1:] {
-				fmt.Fprintf(&b, "%s%s\n", indentPadding, line)
+	flags := usageFlags(root, terminalCmd)
+	if len(flags) > 0 {
+		hasAnyShort := false
+		for _, f := range flags {
+			if f.short != "" {
+				hasAnyShort = true
+				break
 			}
 		}
-		b.WriteString("\n")
+
+		maxFlagLen := 0
+		for _, f := range flags {
+			if n := len(f.displayName(hasAnyShort)); n > maxFlagLen {
+				maxFlagLen = n
+			}
+		}
+
+		// Local flags without a section come first, then each named section, then inherited flags.
+		var local, inherited []flagInfo
+		sections := make(map[string][]flagInfo)
+		for _, f := range flags {
+			switch {
+			case f.inherited:
+				inherited = append(inherited, f)
+			case f.section != "":
+				sections[f.section] = append(sections[f.section], f)
+			default:
+				local = append(local, f)
+			}
+		}
+
+		if len(local) > 0 {
+			b.WriteString(tr("Flags:") + "\n")
+			writeFlagSection(&b, local, maxFlagLen, hasAnyShort)
+			b.WriteString("\n")
+		}
+
+		for _, fo := range terminalCmd.FlagOptions {
+			if section, ok := sections[fo.Section]; ok {
+				b.WriteString(fo.Section + ":\n")
+				writeFlagSection(&b, section, maxFlagLen, hasAnyShort)
+				b.WriteString("\n")
+				delete(sections, fo.Section)
+			}
+		}
+
+		if len(inherited) > 0 {
+			b.WriteString(tr("Inherited Flags:") + "\n")
+			writeFlagSection(&b, inherited, maxFlagLen, hasAnyShort)
+			b.WriteString("\n")
+		}
+	}
+
+	// Point to subcommand help, unless there is no long help flag to point to.
+	if help, ok := helpFlagInfo(root, terminalCmd); ok && len(terminalCmd.SubCommands) > 0 {
+		cmdName := terminalCmd.Name
+		if root.state != nil && len(root.state.path) > 0 {
+			cmdName = getCommandPath(root.state.path)
+		}
+		fmt.Fprintf(&b, tr("Use \"%s [command] %s\" for more information about a command.")+"\n", cmdName, help.name)
+	}
+
+	usage := strings.TrimRight(b.String(), "\n")
+	if footer := inheritedHelpText(root, func(c *Command) string { return c.HelpFooter }); footer != "" {
+		usage += "\n\n" + strings.TrimRight(footer, "\n")
+	}
+	return usage
+}
+
+// inheritedHelpText returns the text selected by field from the terminal command, or from the
+// nearest ancestor that sets it.
+func inheritedHelpText(root *Command, field func(*Command) string) string {
+	path := []*Command{root.terminal()}
+	if root.state != nil && len(root.state.path) > 0 {
+		path = root.state.path
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		if text := field(path[i]); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// usageLine returns the usage pattern of the terminal command, either its Usage or one built from
+// the command path.
+func usageLine(root, terminalCmd *Command) string {
+	if terminalCmd.Usage != "" {
+		return terminalCmd.Usage
+	}
+	usage := terminalCmd.Name
+	if root.state != nil && len(root.state.path) > 0 {
+		usage = getCommandPath(root.state.path)
+	}
+	if len(terminalCmd.SubCommands) > 0 {
+		usage += " <command>"
+	}
+	if terminalCmd.ArgsUsage != "" {
+		usage += " " + terminalCmd.ArgsUsage
+	}
+	if terminalCmd.Flags != nil {
+		usage += " [flags]"
 	}
+	return usage
+}
 
+// usageFlags returns the flags shown in the help of the terminal command, sorted by name. This
+// includes inherited flags and the help flag, and leaves out hidden flags unless help was requested
+// with --help-all.
+func usageFlags(root, terminalCmd *Command) []flagInfo {
 	var flags []flagInfo
+	showHidden := root.state != nil && root.state.helpAll
 	if root.state != nil && len(root.state.path) > 0 {
 		terminalIdx := len(root.state.path) - 1
 		for i, cmd := range root.state.path {
@@ -97,23 +213,34 @@ func DefaultUsage(root *Command) string {
 			}
 			isInherited := i < terminalIdx
 			metaMap := flagOptionMap(cmd.FlagOptions)
+			localFlags := localFlagSet(cmd)
 			cmd.Flags.VisitAll(func(f *flag.Flag) {
 				// Skip local flags from ancestor commands — they don't appear in child help.
-				if isInherited {
-					if m, ok := metaMap[f.Name]; ok && m.Local {
-						return
-					}
+				if isInherited && localFlags[f.Name] {
+					return
+				}
+				if m, ok := metaMap[f.Name]; ok && m.Hidden && !showHidden {
+					return
 				}
 				fi := flagInfo{
 					name:      "--" + f.Name,
 					usage:     f.Usage,
 					defval:    f.DefValue,
 					typeName:  flagTypeName(f),
+					describe:  describeFlag(f),
 					inherited: isInherited,
 				}
 				if m, ok := metaMap[f.Name]; ok {
 					fi.required = m.Required
 					fi.short = m.Short
+					fi.negatable = m.Negatable
+					fi.envVar = m.EnvVar
+					if m.Sensitive {
+						fi.defval = ""
+					}
+					if !isInherited {
+						fi.section = m.Section
+					}
 				}
 				flags = append(flags, fi)
 			})
@@ -122,101 +249,82 @@ func DefaultUsage(root *Command) string {
 		// Pre-parse fallback: show the command's own flags even without state.
 		metaMap := flagOptionMap(terminalCmd.FlagOptions)
 		terminalCmd.Flags.VisitAll(func(f *flag.Flag) {
+			if m, ok := metaMap[f.Name]; ok && m.Hidden {
+				return
+			}
 			fi := flagInfo{
 				name:     "--" + f.Name,
 				usage:    f.Usage,
 				defval:   f.DefValue,
 				typeName: flagTypeName(f),
+				describe: describeFlag(f),
 			}
 			if m, ok := metaMap[f.Name]; ok {
 				fi.required = m.Required
 				fi.short = m.Short
+				fi.negatable = m.Negatable
+				fi.section = m.Section
+				fi.envVar = m.EnvVar
+				if m.Sensitive {
+					fi.defval = ""
+				}
 			}
 			flags = append(flags, fi)
 		})
 	}
 
-	if len(flags) > 0 {
-		slices.SortFunc(flags, func(a, b flagInfo) int {
-			return cmp.Compare(a.name, b.name)
-		})
-
-		hasAnyShort := false
-		for _, f := range flags {
-			if f.short != "" {
-				hasAnyShort = true
-				break
-			}
-		}
-
-		maxFlagLen := 0
-		for _, f := range flags {
-			if n := len(f.displayName(hasAnyShort)); n > maxFlagLen {
-				maxFlagLen = n
-			}
-		}
-
-		hasLocal := false
-		hasInherited := false
-		for _, f := range flags {
-			if f.inherited {
-				hasInherited = true
-			} else {
-				hasLocal = true
-			}
-		}
-
-		if hasLocal {
-			b.WriteString("Flags:\n")
-			writeFlagSection(&b, flags, maxFlagLen, false, hasAnyShort)
-			b.WriteString("\n")
-		}
-
-		if hasInherited {
-			b.WriteString("Inherited Flags:\n")
-			writeFlagSection(&b, flags, maxFlagLen, true, hasAnyShort)
-			b.WriteString("\n")
-		}
-	}
-
-	if len(terminalCmd.SubCommands) > 0 {
-		cmdName := terminalCmd.Name
-		if root.state != nil && len(root.state.path) > 0 {
-			cmdName = getCommandPath(root.state.path)
-		}
-		fmt.Fprintf(&b, "Use \"%s [command] --help\" for more information about a command.\n", cmdName)
+	if fi, ok := helpFlagInfo(root, terminalCmd); ok {
+		flags = append(flags, fi)
 	}
 
-	return strings.TrimRight(b.String(), "\n")
+	slices.SortFunc(flags, func(a, b flagInfo) int {
+		return cmp.Compare(a.name, b.name)
+	})
+	return flags
 }
 
 // writeFlagSection handles the formatting of flag descriptions
-func writeFlagSection(b *strings.Builder, flags []flagInfo, maxLen int, inherited, hasAnyShort bool) {
+func writeFlagSection(b *strings.Builder, flags []flagInfo, maxLen int, hasAnyShort bool) {
 	nameWidth := maxLen + 4
 	wrapWidth := defaultTerminalWidth - nameWidth
 
 	for _, f := range flags {
-		if f.inherited != inherited {
-			continue
-		}
-
 		description := f.usage
+		if f.describe != "" {
+			description += " (" + f.describe + ")"
+		}
+		if f.envVar != "" {
+			description += " (" + fmt.Sprintf(tr("env: %s"), f.envVar) + ")"
+		}
 		if f.required {
-			description += " (required)"
+			description += " (" + tr("required") + ")"
 		} else if !isZeroDefault(f.defval, f.typeName) {
-			description += fmt.Sprintf(" (default: %s)", f.defval)
+			description += " (" + fmt.Sprintf(tr("default: %s"), f.defval) + ")"
 		}
 
 		display := f.displayName(hasAnyShort)
-		lines := textutil.Wrap(description, wrapWidth)
 		padding := strings.Repeat(" ", maxLen-len(display)+4)
-		fmt.Fprintf(b, "  %s%s%s\n", display, padding, lines[0])
+		fmt.Fprintf(b, "  %s%s%s\n", display, padding, textutil.WrapHanging(description, wrapWidth, nameWidth+2))
+	}
+}
 
-		indentPadding := strings.Repeat(" ", nameWidth+2)
-		for _, line := range lines[1:] {
-			fmt.Fprintf(b, "%s%s\n", indentPadding, line)
-		}
+// helpFlagInfo returns the help entry for the Flags section of the terminal command, if the command
+// has a help flag.
+func helpFlagInfo(root, terminalCmd *Command) (flagInfo, bool) {
+	path := []*Command{terminalCmd}
+	if root.state != nil && len(root.state.path) > 0 {
+		path = root.state.path
+	}
+	long, short := terminalCmd.helpFlagNames(combineFlags(path))
+	if long == "" {
+		// A lone short alias cannot be displayed in the Flags section, which lists long names.
+		return flagInfo{}, false
 	}
+	return flagInfo{
+		name:  "--" + long,
+		short: short,
+		usage: fmt.Sprintf(tr("show help for %s"), terminalCmd.Name),
+	}, true
 }
 
 // flagOptionMap builds a lookup map from flag name to its FlagOption.
@@ -234,21 +342,30 @@ type flagInfo struct {
 	usage     string
 	defval    string
 	typeName  string
+	describe  string
+	section   string
+	envVar    string
 	inherited bool
 	required  bool
+	negatable bool
 }
 
 // displayName returns the flag name with optional short alias and type hint. When hasAnyShort is
-// true, flags without a short alias are padded to align with those that have one. Examples: "-v,
-// --verbose", "-o, --output string", "    --config string", "--debug".
+// true, flags without a short alias are padded to align with those that have one. Negatable flags
+// show both forms. Examples: "-v, --verbose", "-o, --output string", "    --config string",
+// "--debug", "--[no-]color".
 func (f flagInfo) displayName(hasAnyShort bool) string {
+	long := f.name
+	if f.negatable {
+		long = "--[no-]" + strings.TrimPrefix(f.name, "--")
+	}
 	var name string
 	if f.short != "" {
-		name = "-" + f.short + ", " + f.name
+		name = "-" + f.short + ", " + long
 	} else if hasAnyShort {
-		name = "    " + f.name
+		name = "    " + long
 	} else {
-		name = f.name
+		name = long
 	}
 	if f.typeName == "" {
 		return name
@@ -259,8 +376,18 @@ func (f flagInfo) displayName(hasAnyShort bool) string {
 // flagTypeName returns a short type name for a flag's value. Bool flags return "" since their type
 // is obvious from usage. This mirrors the approach used by Go's flag.PrintDefaults.
 func flagTypeName(f *flag.Flag) string {
+	// Boolean-like values (e.g., counters) never take an argument, so a type hint would mislead.
+	if isBoolFlag(f) {
+		return ""
+	}
+	v := unwrapFlagValue(f.Value)
 	// Use the type name from the Value interface, which returns the type as a string.
-	typeName := fmt.Sprintf("%T", f.Value)
+	typeName := fmt.Sprintf("%T", v)
+	// Drop type arguments of generic values, like *flagtype.jsonValue[main.Config], which would
+	// otherwise contain dots of their own.
+	if i := strings.Index(typeName, "["); i >= 0 {
+		typeName = typeName[:i]
+	}
 	// The flag package uses unexported types like *flag.boolValue, *flag.stringValue, etc. Extract
 	// just the base name and strip the "Value" suffix.
 	if i := strings.LastIndex(typeName, "."); i >= 0 {
@@ -276,14 +403,49 @@ func flagTypeName(f *flag.Flag) string {
 	return typeName
 }
 
+// FlagDescriber is an optional interface for [flag.Value] implementations that constrain their
+// input. [DefaultUsage] appends the description in parentheses after the flag's usage text, like
+// "output format (one of: json, yaml, table)". Enum and range types in the flagtype package
+// implement it.
+type FlagDescriber interface {
+	Describe() string
+}
+
+// describeFlag returns the constraint description of a flag's value, or "" if it has none.
+func describeFlag(f *flag.Flag) string {
+	v := f.Value
+	for {
+		if d, ok := v.(FlagDescriber); ok {
+			return d.Describe()
+		}
+		u, ok := v.(interface{ Unwrap() flag.Value })
+		if !ok {
+			return ""
+		}
+		v = u.Unwrap()
+	}
+}
+
+// unwrapFlagValue returns the innermost value of wrappers like flagtype.Validate, which should be
+// described by the value they wrap.
+func unwrapFlagValue(v flag.Value) flag.Value {
+	for {
+		u, ok := v.(interface{ Unwrap() flag.Value })
+		if !ok {
+			return v
+		}
+		v = u.Unwrap()
+	}
+}
+
 // isZeroDefault returns true if the default value is the zero value for its type and should be
 // suppressed in help output to reduce noise.
 func isZeroDefault(defval, typeName string) bool {
 	switch {
 	case defval == "":
 		return true
-	case defval == "false" && typeName == "":
-		// Bool flags (typeName is "" for bools).
+	case (defval == "false" || defval == "0") && typeName == "":
+		// Bool flags and boolean-like counters (typeName is "" for both).
 		return true
 	case defval == "0" && (typeName == "int" || typeName == "int64" || typeName == "uint" || typeName == "uint64"):
 		return true
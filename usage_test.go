@@ -3,8 +3,11 @@ package cli
 import (
 	"context"
 	"flag"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/pressly/cli/flagtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -397,6 +400,74 @@ func TestWriteFlagSection(t *testing.T) {
 		require.NotContains(t, output, "-verbose bool")
 	})
 
+	t.Run("type hints for generic values", func(t *testing.T) {
+		t.Parallel()
+
+		type config struct{ Name string }
+		cmd := &Command{
+			Name: "test",
+			Flags: FlagsFunc(func(fset *flag.FlagSet) {
+				fset.Var(flagtype.JSONInto[config](), "config", "inline configuration")
+				fset.Var(flagtype.IntSlice(), "port", "port to listen on")
+				fset.Var(flagtype.Func(strconv.Atoi), "retries", "number of retries")
+				fset.Var(flagtype.Validate(flagtype.Count(), func(string) error { return nil }), "verbose", "more output")
+				fset.Var(flagtype.Validate(flagtype.Enum("a", "b"), func(string) error { return nil }), "mode", "run mode")
+			}),
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+
+		err := Parse(cmd, []string{})
+		require.NoError(t, err)
+
+		output := DefaultUsage(cmd)
+		require.Contains(t, output, "-config json")
+		require.Contains(t, output, "-port intSlice")
+		require.Contains(t, output, "-retries value")
+		require.Contains(t, output, "-mode enum")
+		require.NotContains(t, output, "-verbose count")
+	})
+
+	t.Run("flag value descriptions", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := &Command{
+			Name: "test",
+			Flags: FlagsFunc(func(fset *flag.FlagSet) {
+				fset.Var(flagtype.EnumDefault("json", []string{"json", "yaml"}), "format", "output format")
+				fset.Var(flagtype.Validate(flagtype.IntRange(1, 8), func(string) error { return nil }), "threads", "workers")
+				fset.Var(flagtype.StringSlice(), "tag", "add a tag")
+			}),
+			FlagOptions: []FlagOption{
+				{Name: "threads", Required: true},
+			},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+
+		err := Parse(cmd, []string{"--threads=2"})
+		require.NoError(t, err)
+
+		output := DefaultUsage(cmd)
+		require.Contains(t, output, "output format (one of: json, yaml) (default: json)")
+		require.Contains(t, output, "workers (range: 1-8) (required)")
+		require.Contains(t, output, "add a tag\n")
+	})
+
+	t.Run("log flags list choices once", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := &Command{
+			Name:  "test",
+			Flags: FlagsFunc(LogFlags),
+			Exec:  func(ctx context.Context, s *State) error { return nil },
+		}
+
+		err := Parse(cmd, []string{})
+		require.NoError(t, err)
+
+		output := DefaultUsage(cmd)
+		require.Contains(t, output, "log format (one of: text, json) (default: text)")
+	})
+
 	t.Run("required flags marked", func(t *testing.T) {
 		t.Parallel()
 
@@ -460,7 +531,8 @@ func TestWriteFlagSection(t *testing.T) {
 				fset.Bool("verbose", false, "enable verbose output")
 				fset.String("config", "", "config file path")
 			}),
-			Exec: func(ctx context.Context, s *State) error { return nil },
+			DisableHelpFlag: true,
+			Exec:            func(ctx context.Context, s *State) error { return nil },
 		}
 
 		err := Parse(cmd, []string{})
@@ -478,8 +550,9 @@ func TestWriteFlagSection(t *testing.T) {
 		t.Parallel()
 
 		cmd := &Command{
-			Name: "noflag",
-			Exec: func(ctx context.Context, s *State) error { return nil },
+			Name:            "noflag",
+			DisableHelpFlag: true,
+			Exec:            func(ctx context.Context, s *State) error { return nil },
 		}
 
 		err := Parse(cmd, []string{})
@@ -489,4 +562,237 @@ func TestWriteFlagSection(t *testing.T) {
 		require.NotContains(t, output, "Flags:")
 		require.NotContains(t, output, "Inherited Flags:")
 	})
+
+	t.Run("help flag listed", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := &Command{
+			Name: "serve",
+			Flags: FlagsFunc(func(fset *flag.FlagSet) {
+				fset.String("host", "", "server host")
+			}),
+			FlagOptions: []FlagOption{{Name: "host", Short: "h"}},
+			Exec:        func(ctx context.Context, s *State) error { return nil },
+		}
+
+		err := Parse(cmd, []string{})
+		require.NoError(t, err)
+
+		output := DefaultUsage(cmd)
+		require.Contains(t, output, "  -h, --host string    server host")
+		require.Contains(t, output, "      --help           show help for serve")
+	})
+
+	t.Run("footer follows help flag", func(t *testing.T) {
+		t.Parallel()
+
+		exec := func(ctx context.Context, s *State) error { return nil }
+		newRoot := func() *Command {
+			return &Command{Name: "app", SubCommands: []*Command{{Name: "sub", Exec: exec}}, Exec: exec}
+		}
+
+		root := newRoot()
+		require.NoError(t, Parse(root, nil))
+		require.Contains(t, DefaultUsage(root), `Use "app [command] --help" for more information`)
+
+		root = newRoot()
+		root.HelpFlag = "usage"
+		require.NoError(t, Parse(root, nil))
+		require.Contains(t, DefaultUsage(root), `Use "app [command] --usage" for more information`)
+
+		root = newRoot()
+		root.DisableHelpFlag = true
+		require.NoError(t, Parse(root, nil))
+		require.NotContains(t, DefaultUsage(root), "for more information")
+	})
+
+	t.Run("flag sections", func(t *testing.T) {
+		t.Parallel()
+
+		root := &Command{
+			Name: "db",
+			Flags: FlagsFunc(func(fset *flag.FlagSet) {
+				fset.Bool("verbose", false, "verbose output")
+			}),
+			SubCommands: []*Command{{
+				Name: "query",
+				Flags: FlagsFunc(func(fset *flag.FlagSet) {
+					fset.String("host", "", "database host")
+					fset.Int("port", 0, "database port")
+					fset.String("format", "", "output format")
+					fset.Bool("dry-run", false, "print the query only")
+				}),
+				FlagOptions: []FlagOption{
+					{Name: "port", Section: "Connection Flags"},
+					{Name: "format", Section: "Output Flags"},
+					{Name: "host", Section: "Connection Flags"},
+				},
+				Exec: func(ctx context.Context, s *State) error { return nil },
+			}},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+
+		err := Parse(root, []string{"query"})
+		require.NoError(t, err)
+
+		want := `Usage:
+  db query [flags]
+
+Flags:
+      --dry-run          print the query only
+  -h, --help             show help for query
+
+Connection Flags:
+      --host string      database host
+      --port int         database port
+
+Output Flags:
+      --format string    output format
+
+Inherited Flags:
+      --verbose          verbose output`
+		require.Equal(t, want, DefaultUsage(root))
+	})
+
+	t.Run("hidden flags", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(fset *flag.FlagSet) {
+				fset.Bool("verbose", false, "verbose output")
+				fset.Bool("trace-internals", false, "trace internal state")
+			}),
+			FlagOptions: []FlagOption{{Name: "trace-internals", Hidden: true}},
+			Exec:        func(ctx context.Context, s *State) error { return nil },
+		}
+
+		require.NoError(t, Parse(cmd, []string{"--trace-internals"}))
+		require.True(t, GetFlag[bool](cmd.state, "trace-internals"))
+		require.NotContains(t, DefaultUsage(cmd), "trace-internals")
+
+		require.ErrorIs(t, Parse(cmd, []string{"--help-all"}), ErrHelp)
+		require.Contains(t, DefaultUsage(cmd), "--trace-internals    trace internal state")
+
+		require.ErrorIs(t, Parse(cmd, []string{"--help"}), ErrHelp)
+		require.NotContains(t, DefaultUsage(cmd), "trace-internals")
+	})
+
+	t.Run("header and footer", func(t *testing.T) {
+		t.Parallel()
+
+		root := &Command{
+			Name:       "app",
+			HelpHeader: "app - the example tool",
+			HelpFooter: "Report bugs at https://example.com/issues\n",
+			SubCommands: []*Command{{
+				Name:       "sync",
+				ShortHelp:  "sync files",
+				HelpHeader: "sync - keep directories in step",
+				Exec:       func(ctx context.Context, s *State) error { return nil },
+			}},
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+
+		require.NoError(t, Parse(root, []string{"sync"}))
+		want := `sync - keep directories in step
+
+sync files
+
+Usage:
+  app sync [flags]
+
+Flags:
+  -h, --help    show help for sync
+
+Report bugs at https://example.com/issues`
+		require.Equal(t, want, DefaultUsage(root))
+
+		require.NoError(t, Parse(root, nil))
+		output := DefaultUsage(root)
+		require.True(t, strings.HasPrefix(output, "app - the example tool\n\nUsage:\n"), output)
+		require.True(t, strings.HasSuffix(output, "about a command.\n\nReport bugs at https://example.com/issues"), output)
+	})
+
+	t.Run("long help and example", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := &Command{
+			Name:      "sync",
+			ShortHelp: "sync files",
+			LongHelp: `Sync copies **new and changed** files from the source to the destination, which
+is useful for backups.
+
+- ` + "`mirror`" + ` mode deletes extra files
+- ` + "`update`" + ` mode only adds files`,
+			Example: "```" + `
+# Preview a sync
+sync --dry-run ./src ./dst
+` + "```",
+			Exec: func(ctx context.Context, s *State) error { return nil },
+		}
+		require.NoError(t, Parse(cmd, nil))
+		want := `Sync copies new and changed files from the source to the destination, which is
+useful for backups.
+
+- mirror mode deletes extra files
+- update mode only adds files
+
+Usage:
+  sync [flags]
+
+Examples:
+  # Preview a sync
+  sync --dry-run ./src ./dst
+
+Flags:
+  -h, --help    show help for sync`
+		require.Equal(t, want, DefaultUsage(cmd))
+
+		data, err := HelpJSON(cmd)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"long_help": "Sync copies **new and changed** files`)
+	})
+
+	t.Run("generated usage line", func(t *testing.T) {
+		t.Parallel()
+
+		add := &Command{
+			Name:      "add",
+			ArgsUsage: "<text>",
+			Exec:      func(ctx context.Context, s *State) error { return nil },
+		}
+		root := &Command{
+			Name:        "todo",
+			ArgsUsage:   "ignored",
+			Usage:       "todo <command> [global flags]",
+			SubCommands: []*Command{{Name: "task", SubCommands: []*Command{add}}},
+		}
+
+		require.NoError(t, Parse(root, []string{"task", "add", "milk"}))
+		require.Contains(t, DefaultUsage(root), "Usage:\n  todo task add <text> [flags]\n")
+
+		err := Parse(root, []string{"task", "--help"})
+		require.ErrorIs(t, err, ErrHelp)
+		require.Contains(t, DefaultUsage(root), "Usage:\n  todo task <command> [flags]\n")
+
+		err = Parse(root, []string{"--help"})
+		require.ErrorIs(t, err, ErrHelp)
+		require.Contains(t, DefaultUsage(root), "Usage:\n  todo <command> [global flags]\n")
+	})
+
+	t.Run("environment variable", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := &Command{
+			Name: "app",
+			Flags: FlagsFunc(func(fset *flag.FlagSet) {
+				fset.Int("port", 8080, "listen port")
+			}),
+			FlagOptions: []FlagOption{{Name: "port", EnvVar: "APP_PORT"}},
+			Exec:        func(ctx context.Context, s *State) error { return nil },
+		}
+
+		require.Contains(t, DefaultUsage(cmd), "listen port (env: APP_PORT) (default: 8080)")
+	})
 }
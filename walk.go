@@ -0,0 +1,42 @@
+package cli
+
+import "errors"
+
+// SkipSubCommands is used as a return value from a [Walk] function to skip the subcommands of the
+// command it was called with. It is not returned as an error by Walk.
+var SkipSubCommands = errors.New("skip subcommands")
+
+// Walk calls fn for root and every command below it, depth-first in the order of SubCommands,
+// with each command's path of names from root, like ["todo", "list", "overdue"]. It is meant for
+// documentation generators, lint checks, and completion builders that need the whole tree:
+//
+//	err := cli.Walk(root, func(cmd *cli.Command, path []string) error {
+//	    fmt.Println(strings.Join(path, " "), "-", cmd.ShortHelp)
+//	    return nil
+//	})
+//
+// If fn returns [SkipSubCommands], Walk skips the command's subcommands. Any other error stops the
+// walk and is returned by Walk. The path slice is reused between calls; copy it to keep it. Walk
+// does not require the tree to have been parsed.
+func Walk(root *Command, fn func(cmd *Command, path []string) error) error {
+	if root == nil {
+		return nil
+	}
+	return walk(root, nil, fn)
+}
+
+func walk(cmd *Command, path []string, fn func(*Command, []string) error) error {
+	path = append(path, cmd.Name)
+	if err := fn(cmd, path); err != nil {
+		if errors.Is(err, SkipSubCommands) {
+			return nil
+		}
+		return err
+	}
+	for _, sub := range cmd.SubCommands {
+		if err := walk(sub, path, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	root := &Command{
+		Name: "todo",
+		SubCommands: []*Command{
+			{Name: "add"},
+			{Name: "list", SubCommands: []*Command{
+				{Name: "overdue"},
+				{Name: "done"},
+			}},
+			{Name: "config", SubCommands: []*Command{
+				{Name: "get"},
+			}},
+		},
+	}
+
+	t.Run("all commands", func(t *testing.T) {
+		t.Parallel()
+		var got []string
+		err := Walk(root, func(cmd *Command, path []string) error {
+			require.Equal(t, cmd.Name, path[len(path)-1])
+			got = append(got, strings.Join(path, " "))
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"todo",
+			"todo add",
+			"todo list",
+			"todo list overdue",
+			"todo list done",
+			"todo config",
+			"todo config get",
+		}, got)
+	})
+	t.Run("skip subcommands", func(t *testing.T) {
+		t.Parallel()
+		var got []string
+		err := Walk(root, func(cmd *Command, path []string) error {
+			got = append(got, strings.Join(path, " "))
+			if cmd.Name == "list" {
+				return SkipSubCommands
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"todo", "todo add", "todo list", "todo config", "todo config get"}, got)
+	})
+	t.Run("error stops walk", func(t *testing.T) {
+		t.Parallel()
+		var got [][]string
+		errStop := errors.New("stop")
+		err := Walk(root, func(cmd *Command, path []string) error {
+			got = append(got, slices.Clone(path))
+			if cmd.Name == "overdue" {
+				return errStop
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, errStop)
+		require.Len(t, got, 4)
+		require.Equal(t, []string{"todo", "list", "overdue"}, got[3])
+	})
+	t.Run("nil root", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, Walk(nil, func(*Command, []string) error { return errors.New("called") }))
+	})
+}
@@ -1,7 +1,10 @@
 package xflag
 
 import (
+	"errors"
 	"flag"
+	"strconv"
+	"strings"
 )
 
 // ParseToEnd is a drop-in replacement for flag.Parse. It improves upon the standard behavior by
@@ -13,52 +16,189 @@ import (
 //   - https://github.com/golang/go/issues/63138
 //
 // This is a bit unfortunate, but most users nowadays consuming CLI tools expect this behavior.
-func ParseToEnd(f *flag.FlagSet, arguments []string) error {
-	if err := f.Parse(arguments); err != nil {
-		return err
+//
+// Arguments that look like negative numbers (e.g., -5 or -3.14) are treated as positional
+// arguments, unless a flag with that name is defined or they are the value of a preceding flag. This
+// allows commands like "calc add -5 3".
+//
+// A "--" following a positional argument ends flag parsing, and everything after it is kept as
+// positional arguments. A "--" directly following flags is stripped and parsing continues, which
+// preserves the previous ParseToEnd behavior; the flag package itself stops at any "--". Use
+// [StrictDoubleDash] to always end flag parsing at the first "--".
+func ParseToEnd(f *flag.FlagSet, arguments []string, opts ...Option) error {
+	_, _, err := parseToEnd(f, arguments, newOptions(opts))
+	return err
+}
+
+// Option configures [ParseToEnd], [ParseToEndWithIndex], and [ParseToEndLenient].
+type Option func(*options)
+
+type options struct {
+	strictDoubleDash bool
+	// lenient collects undefined flags instead of failing, see ParseToEndLenient.
+	lenient    bool
+	joinErrors bool
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
 	}
-	if f.NArg() == 0 {
-		return nil
+	return o
+}
+
+// StrictDoubleDash makes the first "--" always end flag parsing, GNU-style. The "--" itself is
+// stripped and everything after it, including any later "--", is kept verbatim as positional
+// arguments. Without this option a "--" that directly follows flags only terminates that run of
+// flags, so in "--verbose -- --debug" the --debug flag is still parsed.
+func StrictDoubleDash() Option {
+	return func(o *options) {
+		o.strictDoubleDash = true
+	}
+}
+
+// JoinErrors makes parsing continue past flags that fail to parse, like unknown flags or invalid
+// values, and return all of the errors joined with [errors.Join], so a user can fix every mistake
+// in one go. Flags that parsed are set as usual. A help request ([flag.ErrHelp]) still stops
+// parsing immediately.
+func JoinErrors() Option {
+	return func(o *options) {
+		o.joinErrors = true
 	}
+}
+
+// ParseToEndWithIndex is like [ParseToEnd] but also returns the index in arguments of each
+// positional argument, in the same order as f.Args(). Callers that care about the relative order of
+// flags and positional arguments, like "--before x --after", can use the positions to reconstruct
+// it.
+func ParseToEndWithIndex(f *flag.FlagSet, arguments []string, opts ...Option) ([]int, error) {
+	positions, _, err := parseToEnd(f, arguments, newOptions(opts))
+	return positions, err
+}
+
+// ParseToEndLenient is like [ParseToEnd] but collects flags that are not defined in f instead of
+// returning an error. The unknown flags are returned in the order they appeared and are not
+// included in f.Args(). A value written inline, like --unknown=value, stays attached to its flag;
+// a separate value, like --unknown value, can't be told apart from a positional argument and is
+// left in f.Args(). Arguments after a terminating "--" are never collected, and -h or -help still
+// return [flag.ErrHelp] unless defined.
+//
+// This is the building block for commands that pass unrecognized flags through to another
+// program.
+func ParseToEndLenient(f *flag.FlagSet, arguments []string, opts ...Option) (unknown []string, err error) {
+	o := newOptions(opts)
+	o.lenient = true
+	_, unknown, err = parseToEnd(f, arguments, o)
+	return unknown, err
+}
+
+func parseToEnd(f *flag.FlagSet, arguments []string, o options) (positions []int, unknown []string, err error) {
 	var args []string
-	remainingArgs := f.Args()
-	for i := 0; i < len(remainingArgs); i++ {
-		arg := remainingArgs[i]
+	// inFlags tracks whether we are in a run of flags, which mirrors how the standard library
+	// consumes arguments: a "--" directly following flags (or at the very start) is swallowed as a
+	// terminator for that run, whereas a "--" following a positional argument ends flag parsing.
+	inFlags := true
+	var errs []error
+	for i := 0; i < len(arguments); i++ {
+		arg := arguments[i]
 		// If the arg looks like a flag, parses like a flag, and quacks like a flag, then it
 		// probably is a flag.
 		//
-		// Note, there's an edge cases here which we EXPLICITLY do not handle, and quite honestly
-		// 99.999% of the time you wouldn't build a CLI with this behavior.
-		//
-		// If you want to treat an unknown flag as a positional argument. For example:
-		//
-		//  $ ./cmd --valid=true arg1 --unknown-flag=foo arg2
-		//
-		// Right now, this will trigger an error. But *some* users might want that unknown flag to
-		// be treated as a positional argument. It's trivial to add this behavior, by using VisitAll
-		// to iterate over all defined flags (regardless if they are set), and then checking if the
-		// flag is in the map of known flags.
-		if len(arg) > 1 && arg[0] == '-' {
-			// If we encounter a "--", treat all subsequent arguments as positional. The "--" itself
-			// is stripped, consistent with the standard library's behavior.
-			if arg == "--" {
-				args = append(args, remainingArgs[i+1:]...)
-				break
+		// An unknown flag, like --unknown-flag=foo in "./cmd --valid=true arg1 --unknown-flag=foo
+		// arg2", is an error unless parsing leniently, in which case it is collected separately.
+		if len(arg) < 2 || arg[0] != '-' || isNegativeNumber(f, arg) {
+			args = append(args, arg)
+			positions = append(positions, i)
+			inFlags = false
+			continue
+		}
+		// If we encounter a "--", treat all subsequent arguments as positional. The "--" itself
+		// is stripped, consistent with the standard library's behavior.
+		if arg == "--" {
+			if inFlags && !o.strictDoubleDash {
+				continue
 			}
-			if err := f.Parse(remainingArgs[i:]); err != nil {
-				return err
+			args = append(args, arguments[i+1:]...)
+			for j := i + 1; j < len(arguments); j++ {
+				positions = append(positions, j)
 			}
-			remainingArgs = f.Args()
-			i = -1 // Reset to handle newly parsed arguments.
+			break
+		}
+		if o.lenient && isUnknownFlag(f, arg) {
+			unknown = append(unknown, arg)
+			inFlags = true
 			continue
 		}
-		args = append(args, arg)
+		n := flagArity(f, arg)
+		if i+n > len(arguments) {
+			n = len(arguments) - i
+		}
+		// Parse exactly one flag (and its value, if any) so the standard library reports errors
+		// like unknown flags or invalid values with its usual messages.
+		if err := f.Parse(arguments[i : i+n]); err != nil {
+			if !o.joinErrors || errors.Is(err, flag.ErrHelp) {
+				return nil, nil, err
+			}
+			errs = append(errs, err)
+		}
+		i += n - 1
+		inFlags = true
+	}
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
 	}
 	if len(args) > 0 {
 		// Use "--" as a sentinel to set the FlagSet's internal args field without unsafe
 		// reflection. When flag.Parse encounters "--" it stops processing and stores the remaining
 		// arguments as positional args, which is exactly what we need.
-		return f.Parse(append([]string{"--"}, args...))
+		if err := f.Parse(append([]string{"--"}, args...)); err != nil {
+			return nil, nil, err
+		}
+		return positions, unknown, nil
+	}
+	return nil, unknown, f.Parse(nil)
+}
+
+// isUnknownFlag reports whether arg names a flag that is not defined in f. The -h and -help flags
+// are never unknown, so the standard library can still report [flag.ErrHelp] for them.
+func isUnknownFlag(f *flag.FlagSet, arg string) bool {
+	name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+	name, _, _ = strings.Cut(name, "=")
+	if name == "h" || name == "help" {
+		return false
+	}
+	return f.Lookup(name) == nil
+}
+
+// flagArity returns the number of arguments a flag argument consumes: 2 for a defined non-boolean
+// flag without an inline value (the flag and its value), otherwise 1.
+func flagArity(f *flag.FlagSet, arg string) int {
+	name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+	if strings.Contains(name, "=") {
+		return 1
+	}
+	fl := f.Lookup(name)
+	if fl == nil {
+		return 1
+	}
+	if bf, ok := fl.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+		return 1
+	}
+	return 2
+}
+
+// isNegativeNumber reports whether arg is a negative number, such as -5 or -3.14, that does not
+// collide with a defined flag.
+func isNegativeNumber(f *flag.FlagSet, arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' {
+		return false
+	}
+	if c := arg[1]; c != '.' && (c < '0' || c > '9') {
+		return false
+	}
+	if _, err := strconv.ParseFloat(arg, 64); err != nil {
+		return false
 	}
-	return nil
+	return f.Lookup(arg[1:]) == nil
 }
@@ -8,6 +8,93 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestParseToEndWithIndex(t *testing.T) {
+	t.Run("interleaved", func(t *testing.T) {
+		fs, c := newFlagset()
+		args := []string{"--flag1", "value1", "arg1", "--flag3", "arg2", "-5", "--flag4=true", "arg3"}
+		positions, err := ParseToEndWithIndex(fs, args)
+		require.NoError(t, err)
+		require.Equal(t, "value1", c.flag1)
+		require.Equal(t, []string{"arg1", "arg2", "-5", "arg3"}, fs.Args())
+		require.Equal(t, []int{2, 4, 5, 7}, positions)
+	})
+	t.Run("double dash", func(t *testing.T) {
+		fs, _ := newFlagset()
+		args := []string{"arg1", "--flag3", "arg2", "--", "--flag4", "arg3"}
+		positions, err := ParseToEndWithIndex(fs, args)
+		require.NoError(t, err)
+		require.Equal(t, []string{"arg1", "arg2", "--flag4", "arg3"}, fs.Args())
+		require.Equal(t, []int{0, 2, 4, 5}, positions)
+	})
+	t.Run("no positional args", func(t *testing.T) {
+		fs, _ := newFlagset()
+		positions, err := ParseToEndWithIndex(fs, []string{"--flag3"})
+		require.NoError(t, err)
+		require.Empty(t, positions)
+	})
+	t.Run("error", func(t *testing.T) {
+		fs, _ := newFlagset()
+		positions, err := ParseToEndWithIndex(fs, []string{"arg1", "--unknown"})
+		require.Error(t, err)
+		require.Nil(t, positions)
+	})
+}
+
+func TestParseToEndLenient(t *testing.T) {
+	t.Run("collects unknown flags", func(t *testing.T) {
+		fs, c := newFlagset()
+		args := []string{"--flag1=value1", "--unknown=foo", "arg1", "-x", "--flag3", "--other", "bar"}
+		unknown, err := ParseToEndLenient(fs, args)
+		require.NoError(t, err)
+		require.Equal(t, "value1", c.flag1)
+		require.True(t, c.flag3)
+		require.Equal(t, []string{"--unknown=foo", "-x", "--other"}, unknown)
+		require.Equal(t, []string{"arg1", "bar"}, fs.Args())
+	})
+	t.Run("not collected after double dash", func(t *testing.T) {
+		fs, _ := newFlagset()
+		unknown, err := ParseToEndLenient(fs, []string{"arg1", "--", "--unknown"})
+		require.NoError(t, err)
+		require.Empty(t, unknown)
+		require.Equal(t, []string{"arg1", "--unknown"}, fs.Args())
+	})
+	t.Run("invalid value of known flag", func(t *testing.T) {
+		fs, _ := newFlagset()
+		_, err := ParseToEndLenient(fs, []string{"--unknown", "--flag3=maybe"})
+		require.Error(t, err)
+	})
+	t.Run("help", func(t *testing.T) {
+		fs, _ := newFlagset()
+		_, err := ParseToEndLenient(fs, []string{"--unknown", "-help"})
+		require.ErrorIs(t, err, flag.ErrHelp)
+	})
+}
+
+func TestJoinErrors(t *testing.T) {
+	t.Run("all errors", func(t *testing.T) {
+		fs, c := newFlagset()
+		args := []string{"--flag3=maybe", "arg1", "--unknown", "--flag1", "value1", "--flag4=perhaps"}
+		err := ParseToEnd(fs, args, JoinErrors())
+		require.Error(t, err)
+		require.Equal(t, `invalid boolean value "maybe" for -flag3: parse error
+flag provided but not defined: -unknown
+invalid boolean value "perhaps" for -flag4: parse error`, err.Error())
+		require.Equal(t, "value1", c.flag1)
+	})
+	t.Run("help", func(t *testing.T) {
+		fs, _ := newFlagset()
+		err := ParseToEnd(fs, []string{"--unknown", "-help"}, JoinErrors())
+		require.ErrorIs(t, err, flag.ErrHelp)
+	})
+	t.Run("no errors", func(t *testing.T) {
+		fs, c := newFlagset()
+		err := ParseToEnd(fs, []string{"arg1", "--flag3"}, JoinErrors())
+		require.NoError(t, err)
+		require.True(t, c.flag3)
+		require.Equal(t, []string{"arg1"}, fs.Args())
+	})
+}
+
 func TestParseToEnd(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		fs := flag.NewFlagSet("name", flag.ContinueOnError)
@@ -173,6 +260,74 @@ func TestParseToEnd(t *testing.T) {
 		require.True(t, c.flag3)
 		require.Equal(t, 0, fs.NArg())
 	})
+	t.Run("strict double dash preserves flags after terminator", func(t *testing.T) {
+		fs, c := newFlagset()
+		args := []string{"--flag1=value1", "--", "--flag3", "--", "arg1"}
+		err := ParseToEnd(fs, args, StrictDoubleDash())
+		require.NoError(t, err)
+		require.Equal(t, "value1", c.flag1)
+		require.False(t, c.flag3)
+		require.Equal(t, []string{"--flag3", "--", "arg1"}, fs.Args())
+	})
+	t.Run("strict double dash at start", func(t *testing.T) {
+		fs, c := newFlagset()
+		positions, err := ParseToEndWithIndex(fs, []string{"--", "--flag3", "arg1"}, StrictDoubleDash())
+		require.NoError(t, err)
+		require.False(t, c.flag3)
+		require.Equal(t, []string{"--flag3", "arg1"}, fs.Args())
+		require.Equal(t, []int{1, 2}, positions)
+	})
+	t.Run("strict double dash after args", func(t *testing.T) {
+		fs, c := newFlagset()
+		err := ParseToEnd(fs, []string{"arg1", "--flag3", "--", "--flag4=false"}, StrictDoubleDash())
+		require.NoError(t, err)
+		require.True(t, c.flag3)
+		require.True(t, c.flag4)
+		require.Equal(t, []string{"arg1", "--flag4=false"}, fs.Args())
+	})
+	t.Run("short alias equals syntax interleaved", func(t *testing.T) {
+		fs := flag.NewFlagSet("name", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		var output string
+		fs.StringVar(&output, "output", "", "output file")
+		fs.StringVar(&output, "o", "", "output file")
+		err := ParseToEnd(fs, []string{"arg1", "-o=file.txt", "arg2"})
+		require.NoError(t, err)
+		require.Equal(t, "file.txt", output)
+		require.Equal(t, []string{"arg1", "arg2"}, fs.Args())
+	})
+	t.Run("negative numbers are positional", func(t *testing.T) {
+		fs, c := newFlagset()
+		args := []string{"-5", "--flag1=value1", "-3.14", "arg1", "-.5", "-1e3"}
+		err := ParseToEnd(fs, args)
+		require.NoError(t, err)
+		require.Equal(t, "value1", c.flag1)
+		require.Equal(t, []string{"-5", "-3.14", "arg1", "-.5", "-1e3"}, fs.Args())
+	})
+	t.Run("negative number as flag value", func(t *testing.T) {
+		fs := flag.NewFlagSet("name", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		offset := fs.Int("offset", 0, "offset")
+		err := ParseToEnd(fs, []string{"arg1", "--offset", "-10", "-2"})
+		require.NoError(t, err)
+		require.Equal(t, -10, *offset)
+		require.Equal(t, []string{"arg1", "-2"}, fs.Args())
+	})
+	t.Run("defined numeric flag wins over negative number", func(t *testing.T) {
+		fs := flag.NewFlagSet("name", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		one := fs.Bool("1", false, "single line mode")
+		err := ParseToEnd(fs, []string{"-1", "-2"})
+		require.NoError(t, err)
+		require.True(t, *one)
+		require.Equal(t, []string{"-2"}, fs.Args())
+	})
+	t.Run("dash prefixed words are still flags", func(t *testing.T) {
+		fs, _ := newFlagset()
+		err := ParseToEnd(fs, []string{"-inf"})
+		require.Error(t, err)
+		require.Equal(t, "flag provided but not defined: -inf", err.Error())
+	})
 	t.Run("duplicate flags last wins", func(t *testing.T) {
 		fs, c := newFlagset()
 		args := []string{
@@ -0,0 +1,78 @@
+package xflag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SplitCommandString splits a command line into arguments following POSIX shell quoting rules,
+// without performing any expansion (no variables, globs, or command substitution). The result can
+// be passed to [ParseToEnd] or cli.Parse, which is useful for applications that embed a REPL or
+// read commands from a configuration file.
+//
+// Rules:
+//   - Unquoted whitespace (spaces, tabs, newlines) separates arguments
+//   - Single quotes preserve everything literally until the closing quote
+//   - Double quotes preserve everything except a backslash before ", \, $, ` or a newline
+//   - Outside quotes, a backslash preserves the next character literally
+//   - A backslash followed by a newline (outside single quotes) is a line continuation and is removed
+//
+// An error is returned for an unterminated quote or a trailing backslash.
+//
+//	args, err := xflag.SplitCommandString(`commit -m "fix: handle \"quoted\" input"`)
+//	// args = []string{"commit", "-m", `fix: handle "quoted" input`}
+func SplitCommandString(s string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+			if r == '\n' {
+				// Line continuation. It writes nothing, so it does not start an argument.
+				continue
+			}
+			if quote == '"' && !strings.ContainsRune("\"\\$`", r) {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(r)
+			inArg = true
+		case r == '\\' && quote != '\'':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
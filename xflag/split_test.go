@@ -0,0 +1,60 @@
+package xflag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCommandString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{name: "empty", input: "", expected: nil},
+		{name: "whitespace only", input: " \n\t\r\n", expected: nil},
+		{name: "simple", input: "task add --tags=x buy milk", expected: []string{"task", "add", "--tags=x", "buy", "milk"}},
+		{name: "collapses whitespace", input: "  a \t b\n\nc  ", expected: []string{"a", "b", "c"}},
+		{name: "double quotes", input: `add "buy milk" now`, expected: []string{"add", "buy milk", "now"}},
+		{name: "single quotes literal", input: `'a\"b $HOME'`, expected: []string{`a\"b $HOME`}},
+		{name: "escaped space", input: `a\ b c`, expected: []string{"a b", "c"}},
+		{name: "escaped quote in double quotes", input: `"say \"hi\""`, expected: []string{`say "hi"`}},
+		{name: "backslash kept before ordinary char in double quotes", input: `"C:\path\to"`, expected: []string{`C:\path\to`}},
+		{name: "escaped backslash in double quotes", input: `"a\\b"`, expected: []string{`a\b`}},
+		{name: "empty quoted args", input: `'' "" x`, expected: []string{"", "", "x"}},
+		{name: "adjacent quoted parts", input: `--name="a b"c'd e'`, expected: []string{"--name=a bcd e"}},
+		{name: "line continuation", input: "a \\\nb", expected: []string{"a", "b"}},
+		{name: "line continuation before indentation", input: "run --foo bar \\\n    --baz qux", expected: []string{"run", "--foo", "bar", "--baz", "qux"}},
+		{name: "line continuation within argument", input: "a\\\nb c", expected: []string{"ab", "c"}},
+		{name: "line continuation in double quotes", input: "\"a\\\nb\"", expected: []string{"ab"}},
+		{name: "unicode", input: `héllo "wörld ✓"`, expected: []string{"héllo", "wörld ✓"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := SplitCommandString(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, got)
+		})
+	}
+
+	t.Run("unterminated double quote", func(t *testing.T) {
+		t.Parallel()
+		_, err := SplitCommandString(`echo "oops`)
+		require.EqualError(t, err, `unterminated " quote`)
+	})
+	t.Run("unterminated single quote", func(t *testing.T) {
+		t.Parallel()
+		_, err := SplitCommandString(`echo 'oops`)
+		require.EqualError(t, err, `unterminated ' quote`)
+	})
+	t.Run("trailing backslash", func(t *testing.T) {
+		t.Parallel()
+		_, err := SplitCommandString(`echo \`)
+		require.EqualError(t, err, "trailing backslash")
+	})
+}